@@ -5,59 +5,62 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"path/filepath"
+	"os"
+	"time"
 
-	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/exitcode"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
+func main() {
+	var (
+		spec        project.Spec
+		labels      string
+		annotations string
+		createOpts  project.CreateOptions
+	)
+
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.StringVar(&spec.Name, "name", "myproject", "name of the project")
+	flag.StringVar(&spec.DisplayName, "display-name", "MyProject", "display name of the project")
+	flag.StringVar(&spec.Description, "description", "This is a description of myproject", "description of the project")
+	flag.StringVar(&labels, "labels", "", "comma separated key=value labels to set on the project")
+	flag.StringVar(&annotations, "annotations", "", "comma separated key=value annotations to set on the project")
+	flag.BoolVar(&createOpts.WaitActive, "wait-active", false, "wait until the project phase is Active")
+	flag.DurationVar(&createOpts.Timeout, "timeout", time.Minute, "how long -wait-active waits")
 	flag.Parse()
 
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
+	var err error
+	if spec.Labels, err = project.ParseKeyValues(labels); err != nil {
+		log.Fatalf("Error parsing -labels: %v", err)
 	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
-func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
-	projectRequest := projectv1.ProjectRequest{
-		ObjectMeta:  metav1.ObjectMeta{Name: name},
-		DisplayName: displayName,
-		Description: description,
+	if spec.Annotations, err = project.ParseKeyValues(annotations); err != nil {
+		log.Fatalf("Error parsing -annotations: %v", err)
 	}
-	return &projectRequest
-}
 
-func main() {
-	// 1. Getting OpenShift Project client-set
-	clientset, err := getProjectClientSet()
+	// 1. Getting OpenShift Project and Kubernetes client-sets
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
+	kubeClientset, err := opts.KubeClientSet()
+	if err != nil {
+		log.Fatalf("Error creating kubernetes client: %v", err)
+	}
 
-	// 2. Creating ProjectRequest structure
+	// 2. Creating ProjectRequest (and waiting for the project to become Active)
 	ctx := context.Background()
-	pr := createProjectRequest("myproject", "MyProject", "This is a description of myproject")
-
-	// 3. Creating ProjectRequest
-	p, err := clientset.ProjectV1().ProjectRequests().Create(ctx, pr, metav1.CreateOptions{})
+	p, err := project.Create(ctx, clientset, kubeClientset, spec, createOpts)
 	if err != nil {
-		log.Fatalf("Error creating project: %v", err)
+		log.Printf("Error creating project: %v", err)
+		os.Exit(exitcode.For(err))
 	}
 
+	if createOpts.WaitActive {
+		fmt.Printf("%s created (phase: Active)\n", p.Name)
+		return
+	}
 	fmt.Printf("%s created\n", p.Name)
 }
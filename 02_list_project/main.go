@@ -2,116 +2,90 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/demo"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
+const csvFile = "projects.csv"
 
-func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
-	projectRequest := projectv1.ProjectRequest{
-		ObjectMeta:  metav1.ObjectMeta{Name: name},
-		DisplayName: displayName,
-		Description: description,
-	}
-	return &projectRequest
-}
+func main() {
+	var (
+		err       error
+		output    string
+		chunkSize int64
+		filter    project.Filter
+	)
+
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.StringVar(&output, "o", "table", "output format: "+printer.Formats)
+	flag.Int64Var(&chunkSize, "chunk-size", project.DefaultPageSize, "number of projects requested per page, 0 disables paging; the project API may still return all projects at once")
+	filter.AddFlags(flag.CommandLine)
+	flag.Parse()
 
-func createProjectsFromCSV(clientset *projectclientset.Clientset, csvFile string) error {
-	file, err := os.Open(csvFile)
+	matcher, err := filter.Compile()
 	if err != nil {
-		return err
-	}
-	defer file.Close()
-	r := csv.NewReader(file)
-
-	if _, err := r.Read(); err != nil {
-		if err != io.EOF {
-			return err
-		}
-	}
-
-	for {
-		row, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-
-		ctx := context.Background()
-
-		pr := createProjectRequest(row[0], row[1], row[2])
-
-		newProject, err := clientset.ProjectV1().ProjectRequests().Create(ctx, pr, metav1.CreateOptions{})
-		if err != nil {
-			return err
-		}
-
-		fmt.Printf("%s created\n", newProject.Name)
+		log.Fatalf("Error parsing filter: %v", err)
 	}
-	return nil
-}
-
-const csvFile = "projects.csv"
-
-func main() {
-	var err error
 
 	// 1. Getting OpenShift Project client-set
-	clientset, err := getProjectClientSet()
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
+	kubeClientset, err := opts.KubeClientSet()
+	if err != nil {
+		log.Fatalf("Error creating kubernetes client: %v", err)
+	}
 
 	// 2. Creating projects from CSV file
-	err = createProjectsFromCSV(clientset, csvFile)
+	ctx := context.Background()
+	names, err := demo.CreateProjectsFromCSV(ctx, clientset, kubeClientset, csvFile)
 	if err != nil {
 		log.Fatalf("Error creating projects: %v", err)
 	}
 
-	time.Sleep(5 * time.Second)
+	// Waiting for the projects to become Active
+	err = project.WaitForActive(ctx, clientset, names, project.WaitOptions{
+		Timeout: time.Minute,
+		OnReady: func(p *projectv1.Project, ready, total int) {
+			fmt.Printf("%s is ready (%d/%d)\n", p.Name, ready, total)
+		},
+	})
+	if err != nil {
+		log.Fatalf("Error waiting for projects: %v", err)
+	}
 
-	// 3 Getting Project list
-	ctx := context.Background()
-	projects, err := clientset.ProjectV1().Projects().List(ctx, metav1.ListOptions{})
+	// 3 Getting Project list page by page
+	out, err := printer.New(os.Stdout, output)
+	if err != nil {
+		log.Fatalf("Error creating printer: %v", err)
+	}
+	err = project.ListPages(ctx, clientset, matcher, chunkSize, func(page []projectv1.Project) error {
+		// 4. Printing Project info as each page arrives
+		for i := range page {
+			if err := out.PrintProject(&page[i]); err != nil {
+				return err
+			}
+		}
+		if !out.Streaming() {
+			return nil
+		}
+		return out.Flush()
+	})
 	if err != nil {
 		log.Fatalf("Error listing projects %v", err)
 	}
-
-	// 4. Printing Project info
-	fmt.Printf("NAME\tDISPLAY NAME\tDESCRIPTION\n")
-	fmt.Println("----------------------------------------------")
-	for _, p := range projects.Items {
-		displayName := p.Annotations["openshift.io/display-name"]
-		description := p.Annotations["openshift.io/description"]
-		fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
+	if err := out.Flush(); err != nil {
+		log.Fatalf("Error printing projects %v", err)
 	}
 }
@@ -5,37 +5,17 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"path/filepath"
+	"time"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
 func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
 	projectRequest := projectv1.ProjectRequest{
 		ObjectMeta:  metav1.ObjectMeta{Name: name},
@@ -45,16 +25,6 @@ func createProjectRequest(name string, displayName string, description string) *
 	return &projectRequest
 }
 
-func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
-}
-
-func pointerInt64(i int64) *int64 {
-	return &i
-}
-
 func main() {
 	const (
 		projectName = "myproject"
@@ -62,23 +32,27 @@ func main() {
 		description = "This is a description of myproject"
 	)
 
-	clientset, err := getProjectClientSet()
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
 
-	ctx := context.Background()
+	// Give up if the project is not active within 20 seconds. Dropped
+	// connections in between are resumed by the watcher.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
 	pr := createProjectRequest(projectName, displayName, description)
 
 	fmt.Println("Watching project event")
-	w, err := clientset.ProjectV1().Projects().Watch(ctx, metav1.ListOptions{
-		FieldSelector:  fmt.Sprintf("metadata.name=%s", projectName),
-		TimeoutSeconds: pointerInt64(20), // 20 sec
+	w := projectwatch.New(ctx, clientset, projectwatch.Options{
+		ListOptions: metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("metadata.name=%s", projectName),
+		},
 	})
-	if err != nil {
-		panic(err)
-	}
-
 	defer w.Stop()
 
 	fmt.Println("Creating project")
@@ -88,8 +62,11 @@ func main() {
 	}
 
 	for event := range w.ResultChan() {
-		proj, ok := event.Object.(*projectv1.Project)
-		if !ok {
+		proj, skip, err := projectwatch.Next(event)
+		if err != nil {
+			log.Fatalf("Project watch failed: %v", err)
+		}
+		if skip {
 			continue
 		}
 
@@ -6,48 +6,25 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"path/filepath"
-	"syscall"
+	"time"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/lifecycle"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
 	projectv1 "github.com/openshift/api/project/v1"
 	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
 func pointerInt64(i int64) *int64 {
 	return &i
 }
 
 func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("NAME\tDISPLAY NAME\tDESCRIPTION\n")
-	fmt.Println("----------------------------------------------")
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
+	printer.PrintProject(os.Stdout, "table", p)
 }
 
 func stopProjectWatch(w watch.Interface) {
@@ -56,39 +33,50 @@ func stopProjectWatch(w watch.Interface) {
 }
 
 func main() {
-	var err error
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.Parse()
 
-	clientset, err := getProjectClientSet()
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
 
-	ctx := context.Background()
+	if err := run(clientset); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run watches projects until interrupted.
+func run(clientset projectclientset.Interface) error {
+	lm := lifecycle.New(5 * time.Second)
+	ctx := lm.Context()
+	defer func() {
+		lm.Shutdown()
+		if err := lm.Wait(); err != nil {
+			log.Printf("Shutdown incomplete: %v", err)
+		}
+	}()
 
 	fmt.Println("Creating a project watch")
-	w, err := clientset.ProjectV1().Projects().Watch(ctx, metav1.ListOptions{
-		TimeoutSeconds: pointerInt64(600), // 600 sec
+	w := projectwatch.New(ctx, clientset, projectwatch.Options{
+		ListOptions: metav1.ListOptions{
+			TimeoutSeconds: pointerInt64(600), // 600 sec
+		},
 	})
-	if err != nil {
-		panic(err)
-	}
-
-	defer stopProjectWatch(w)
 
-	fmt.Println("Catching signals to close watch connection")
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-signals
-		fmt.Println("Signal caught")
+	lm.OnShutdown("project watch", func(context.Context) error {
 		stopProjectWatch(w)
-		os.Exit(0)
-	}()
+		return nil
+	})
 
 	fmt.Println("Waiting for project events")
 	for event := range w.ResultChan() {
-		proj, ok := event.Object.(*projectv1.Project)
-		if !ok {
+		proj, skip, err := projectwatch.Next(event)
+		if err != nil {
+			return fmt.Errorf("project watch failed: %w", err)
+		}
+		if skip {
 			continue
 		}
 
@@ -104,5 +92,6 @@ func main() {
 		}
 		printProject(proj)
 	}
-	fmt.Println("Timeout")
+	fmt.Println("Watch ended")
+	return nil
 }
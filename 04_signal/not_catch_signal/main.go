@@ -5,46 +5,23 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"path/filepath"
+	"os"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
 func pointerInt64(i int64) *int64 {
 	return &i
 }
 
 func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("NAME\tDISPLAY NAME\tDESCRIPTION\n")
-	fmt.Println("----------------------------------------------")
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
+	printer.PrintProject(os.Stdout, "table", p)
 }
 
 func stopProjectWatch(w watch.Interface) {
@@ -55,7 +32,11 @@ func stopProjectWatch(w watch.Interface) {
 func main() {
 	var err error
 
-	clientset, err := getProjectClientSet()
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
@@ -63,19 +44,21 @@ func main() {
 	ctx := context.Background()
 
 	fmt.Println("Creating a project watch")
-	w, err := clientset.ProjectV1().Projects().Watch(ctx, metav1.ListOptions{
-		TimeoutSeconds: pointerInt64(600), // 600 sec
+	w := projectwatch.New(ctx, clientset, projectwatch.Options{
+		ListOptions: metav1.ListOptions{
+			TimeoutSeconds: pointerInt64(600), // 600 sec
+		},
 	})
-	if err != nil {
-		panic(err)
-	}
 
 	defer stopProjectWatch(w)
 
 	fmt.Println("Waiting for project events")
 	for event := range w.ResultChan() {
-		proj, ok := event.Object.(*projectv1.Project)
-		if !ok {
+		proj, skip, err := projectwatch.Next(event)
+		if err != nil {
+			log.Fatalf("Project watch failed: %v", err)
+		}
+		if skip {
 			continue
 		}
 
@@ -91,5 +74,5 @@ func main() {
 		}
 		printProject(proj)
 	}
-	fmt.Println("Timeout")
+	fmt.Println("Watch ended")
 }
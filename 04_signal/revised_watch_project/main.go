@@ -2,106 +2,27 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"os"
-	"os/signal"
-	"path/filepath"
-	"syscall"
+	"time"
 
-	projectv1 "github.com/openshift/api/project/v1"
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/demo"
+	"github.com/fminamot/openshift-clientgo-demo/internal/lifecycle"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
 	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
-func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
-	projectRequest := projectv1.ProjectRequest{
-		ObjectMeta:  metav1.ObjectMeta{Name: name},
-		DisplayName: displayName,
-		Description: description,
-	}
-	return &projectRequest
-}
-
-func createProjectsFromCSV(clientset *projectclientset.Clientset, csvFile string) (map[string]int, error) {
-	pnames := map[string]int{}
-	counter := 0
-
-	file, err := os.Open(csvFile)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-	r := csv.NewReader(file)
-
-	if _, err := r.Read(); err != nil {
-		if err != io.EOF {
-			return nil, err
-		}
-	}
-
-	for {
-		row, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-
-		ctx := context.Background()
-
-		pr := createProjectRequest(row[0], row[1], row[2])
-
-		newProject, err := clientset.ProjectV1().ProjectRequests().Create(ctx, pr, metav1.CreateOptions{})
-		if err != nil {
-			return nil, err
-		}
-
-		pnames[newProject.Name] = counter
-		counter++
-
-		fmt.Printf("%s created\n", newProject.Name)
-	}
-	return pnames, nil
-}
-
 func pointerInt64(i int64) *int64 {
 	return &i
 }
 
-/*
-func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
-}
-*/
-
 func stopProjectWatch(w watch.Interface) {
 	fmt.Println("Stopping the project watch")
 	w.Stop()
@@ -109,61 +30,82 @@ func stopProjectWatch(w watch.Interface) {
 
 func main() {
 	const csvFile = "projects.csv"
-	var err error
 
-	clientset, err := getProjectClientSet()
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
+	kubeClientset, err := opts.KubeClientSet()
+	if err != nil {
+		log.Fatalf("Error creating kubernetes client: %v", err)
+	}
+
+	if err := run(clientset, kubeClientset, csvFile); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	ctx := context.Background()
+// run creates the projects in csvFile and watches until they are all
+// Active or it is interrupted.
+func run(clientset projectclientset.Interface, kubeClientset kubernetes.Interface, csvFile string) error {
+	lm := lifecycle.New(5 * time.Second)
+	ctx := lm.Context()
+	defer func() {
+		lm.Shutdown()
+		if err := lm.Wait(); err != nil {
+			log.Printf("Shutdown incomplete: %v", err)
+		}
+	}()
 
 	fmt.Println("Creating a project watch")
-	w, err := clientset.ProjectV1().Projects().Watch(ctx, metav1.ListOptions{
-		TimeoutSeconds: pointerInt64(600), // 600 sec
+	w := projectwatch.New(ctx, clientset, projectwatch.Options{
+		ListOptions: metav1.ListOptions{
+			TimeoutSeconds: pointerInt64(600), // 600 sec
+		},
 	})
-	if err != nil {
-		panic(err)
-	}
 
-	defer stopProjectWatch(w)
+	lm.OnShutdown("project watch", func(context.Context) error {
+		stopProjectWatch(w)
+		return nil
+	})
 
 	fmt.Println("Creating projects")
-	pnames, err := createProjectsFromCSV(clientset, csvFile)
+	names, err := demo.CreateProjectsFromCSV(ctx, clientset, kubeClientset, csvFile)
 	if err != nil {
-		log.Fatalf("Error creating projects: %v", err)
+		return fmt.Errorf("error creating projects: %w", err)
+	}
+	pending := map[string]bool{}
+	for _, name := range names {
+		pending[name] = true
 	}
-
-	fmt.Println("Catching signals to close watch connection")
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-signals
-		fmt.Println("Signal caught, stopping watch")
-		w.Stop()
-		os.Exit(0)
-	}()
 
 	fmt.Println("Waiting for project events")
 	for event := range w.ResultChan() {
-		proj, ok := event.Object.(*projectv1.Project)
-		if !ok {
+		proj, skip, err := projectwatch.Next(event)
+		if err != nil {
+			return fmt.Errorf("project watch failed: %w", err)
+		}
+		if skip {
 			continue
 		}
 
 		switch event.Type {
 		case watch.Added, watch.Modified:
-			_, found := pnames[proj.Name]
-			if found && proj.Status.Phase == corev1.NamespaceActive {
+			if pending[proj.Name] && proj.Status.Phase == corev1.NamespaceActive {
 				fmt.Printf("%s is ready (phase: %s)\n", proj.Name, proj.Status.Phase)
-				delete(pnames, proj.Name)
-				if len(pnames) == 0 {
-					return
+				delete(pending, proj.Name)
+				if len(pending) == 0 {
+					return nil
 				}
 			}
 		case watch.Deleted:
 			fmt.Printf("%s is deleted unexpectedly\n", proj.Name)
 		}
 	}
-	fmt.Println("Timeout")
+	fmt.Println("Watch ended")
+	return nil
 }
@@ -4,42 +4,33 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"path/filepath"
 
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
 	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
 
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
+func main() {
+	var (
+		err    error
+		filter project.Filter
+	)
+
+	stopCh := make(chan struct{})
+
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	filter.AddFlags(flag.CommandLine)
 	flag.Parse()
 
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	matcher, err := filter.Compile()
 	if err != nil {
-		return nil, err
+		log.Fatalf("Error parsing filter: %v", err)
 	}
 
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
-func main() {
-	var err error
-
-	stopCh := make(chan struct{})
-
-	clientset, err := getProjectClientSet()
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
@@ -62,10 +53,13 @@ func main() {
 		log.Println("Failed to sync cache")
 	}
 
-	log.Printf("Listing all projects from lister")
+	log.Printf("Listing projects from lister")
 	lister := factory.Project().V1().Projects().Lister()
 
-	list, _ := lister.List(labels.Everything())
+	list, err := project.ListFromLister(lister, matcher)
+	if err != nil {
+		log.Fatalf("Error listing projects: %v", err)
+	}
 	for _, p := range list {
 		fmt.Println(p.Name)
 	}
@@ -1,159 +1,94 @@
 package main
 
 import (
-	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/demo"
+	"github.com/fminamot/openshift-clientgo-demo/internal/lifecycle"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
 	projectv1 "github.com/openshift/api/project/v1"
 	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
-	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"k8s.io/client-go/kubernetes"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
+func main() {
+	const csvFile = "../projects.csv"
+
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
 	flag.Parse()
 
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
-func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
-	projectRequest := projectv1.ProjectRequest{
-		ObjectMeta:  metav1.ObjectMeta{Name: name},
-		DisplayName: displayName,
-		Description: description,
+		log.Fatalf("Error creating project client: %v", err)
 	}
-	return &projectRequest
-}
-
-func createProjectsFromCSV(clientset *projectclientset.Clientset, csvFile string) (map[string]int, error) {
-	pnames := map[string]int{}
-	counter := 0
-
-	file, err := os.Open(csvFile)
+	kubeClientset, err := opts.KubeClientSet()
 	if err != nil {
-		return nil, err
+		log.Fatalf("Error creating kubernetes client: %v", err)
 	}
-	defer file.Close()
-	r := csv.NewReader(file)
 
-	if _, err := r.Read(); err != nil {
-		if err != io.EOF {
-			return nil, err
-		}
+	if err := run(clientset, kubeClientset, csvFile); err != nil {
+		log.Fatal(err)
 	}
-
-	for {
-		row, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-
-		ctx := context.Background()
-
-		pr := createProjectRequest(row[0], row[1], row[2])
-
-		newProject, err := clientset.ProjectV1().ProjectRequests().Create(ctx, pr, metav1.CreateOptions{})
-		if err != nil {
-			return nil, err
-		}
-
-		pnames[newProject.Name] = counter
-		counter++
-
-		fmt.Printf("%s created\n", newProject.Name)
-	}
-	return pnames, nil
 }
 
-func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
-}
-
-func main() {
-	const csvFile = "../projects.csv"
-
-	signalCtx := signals.SetupSignalHandler()
-	ctx, cancel := context.WithCancel(signalCtx)
-
-	go func() {
-		<-ctx.Done()
-		fmt.Println("Informer was stopped. Shutting down normally")
-		time.Sleep(60 * time.Second)
+// run creates the projects in csvFile and lists them once they are
+// Active.
+func run(clientset projectclientset.Interface, kubeClientset kubernetes.Interface, csvFile string) error {
+	lm := lifecycle.New(10 * time.Second)
+	ctx := lm.Context()
+	defer func() {
+		lm.Shutdown()
+		if err := lm.Wait(); err != nil {
+			log.Printf("Shutdown incomplete: %v", err)
+		}
 	}()
 
-	clientset, err := getProjectClientSet()
-	if err != nil {
-		log.Fatalf("Error creating project client: %v", err)
-	}
-
 	fmt.Println("Creating informer from informer factory")
 	factory := projectinformers.NewSharedInformerFactory(clientset, 0)
 	informer := factory.Project().V1().Projects().Informer()
 	lister := factory.Project().V1().Projects().Lister()
 
 	fmt.Println("Starting informers")
-	go factory.Start(ctx.Done())
-
-	defer func() {
-		cancel()
-		factory.Shutdown()
-		log.Println("Informer was stopped")
-	}()
+	lm.AddInformerFactory("informer factory", factory)
 
 	ok := cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
 	fmt.Printf("Cache is synced: %v\n", ok)
 
 	fmt.Println("Creating projects")
-	pnames, err := createProjectsFromCSV(clientset, csvFile)
+	names, err := demo.CreateProjectsFromCSV(ctx, clientset, kubeClientset, csvFile)
 	if err != nil {
-		log.Fatalf("Error creating projects: %v", err)
+		return fmt.Errorf("error creating projects: %w", err)
 	}
 
-	time.Sleep(5 * time.Second)
+	fmt.Println("Waiting for projects to become Active")
+	err = project.WaitForActiveInformer(ctx, informer, names, project.WaitOptions{
+		Timeout: time.Minute,
+		OnReady: func(p *projectv1.Project, ready, total int) {
+			fmt.Printf("%s is ready (%d/%d)\n", p.Name, ready, total)
+		},
+	})
+	if err != nil {
+		log.Printf("Error waiting for projects: %v", err)
+	}
 
-	fmt.Printf("\nNAME\tDISPLAY NAME\tDESCRIPTION\n")
-	fmt.Println("----------------------------------------------")
-	for {
-		list, _ := lister.List(labels.Everything())
-		for _, p := range list {
-			_, found := pnames[p.Name]
-			if found {
-				printProject(p)
-				delete(pnames, p.Name)
-			}
-		}
-		if len(pnames) == 0 {
-			break
+	fmt.Println()
+	table, _ := printer.New(os.Stdout, "table")
+	for _, name := range names {
+		if p, err := lister.Get(name); err == nil {
+			table.PrintProject(p)
 		}
-		time.Sleep(3 * time.Second)
 	}
+	table.Flush()
 	fmt.Println("Done")
+	return nil
 }
@@ -5,39 +5,20 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"path/filepath"
+	"os"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventhandler"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
 func createProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
 	projectRequest := projectv1.ProjectRequest{
 		ObjectMeta:  metav1.ObjectMeta{Name: name},
@@ -48,11 +29,7 @@ func createProjectRequest(name string, displayName string, description string) *
 }
 
 func printProject(p *projectv1.Project) {
-	displayName := p.Annotations["openshift.io/display-name"]
-	description := p.Annotations["openshift.io/description"]
-	fmt.Printf("NAME\tDISPLAY NAME\tDESCRIPTION\n")
-	fmt.Println("----------------------------------------------")
-	fmt.Printf("%s\t%s\t%s\n", p.Name, displayName, description)
+	printer.PrintProject(os.Stdout, "table", p)
 }
 
 func main() {
@@ -67,7 +44,11 @@ func main() {
 	ctx, cancel := context.WithCancel(signalCtx)
 	defer cancel() // to stop informer
 
-	clientset, err := getProjectClientSet()
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
@@ -78,9 +59,10 @@ func main() {
 
 	done := make(chan string) // project name
 
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			proj := obj.(*projectv1.Project)
+	// Handlers run on the dispatcher goroutine; once main stops reading
+	// done, later events are dropped instead of blocking the informer.
+	dispatcher := eventhandler.NewDispatcher(eventhandler.ProjectHandlerFuncs{
+		AddFunc: func(proj *projectv1.Project, _ bool) {
 			fmt.Printf("[Event] %s is added\n", proj.Name)
 
 			if proj.Name == projectName &&
@@ -88,21 +70,21 @@ func main() {
 				done <- proj.Name // sending project name to done channel
 			}
 		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			oldP := oldObj.(*projectv1.Project)
-			newP := newObj.(*projectv1.Project)
-			fmt.Printf("[Event] %s is modified\n", newP.Name)
+		UpdateFunc: eventhandler.DiffingUpdateFunc(func(_, newP *projectv1.Project, changes eventhandler.ChangeSet) {
+			fmt.Printf("[Event] %s is modified: %s\n", newP.Name, changes)
 
 			if newP.Name != projectName {
 				return
 			}
 
-			if oldP.Status.Phase != newP.Status.Phase &&
+			if changes.Has(eventhandler.FieldPhase) &&
 				newP.Status.Phase == corev1.NamespaceActive {
 				done <- newP.Name // sending project name to done channel
 			}
-		},
-	})
+		}),
+	}, eventhandler.DispatcherOptions{Policy: eventhandler.DropOldest})
+	informer.AddEventHandler(dispatcher)
+	go dispatcher.Run(ctx)
 
 	fmt.Println("Starting informers")
 	go factory.Start(ctx.Done()) // arg is stop channel
@@ -4,43 +4,28 @@ import (
 	"context"
 	"flag"
 	"log"
-	"path/filepath"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventhandler"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
 func main() {
 
 	signalCtx := signals.SetupSignalHandler()
 	ctx, cancel := context.WithCancel(signalCtx)
 
-	clientset, err := getProjectClientSet()
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	dispatcherOpts := eventhandler.DispatcherOptions{Policy: eventhandler.Coalesce}
+	dispatcherOpts.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
@@ -56,25 +41,26 @@ func main() {
 		log.Println("Informer was stopped")
 	}()
 
-	projEvent := make(chan struct{})
-
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			p := obj.(*projectv1.Project)
+	dispatcher := eventhandler.NewDispatcher(eventhandler.ProjectHandlerFuncs{
+		AddFunc: func(p *projectv1.Project, isInitialList bool) {
+			if isInitialList {
+				log.Printf("[Event] %s is listed, => %s\n", p.Name, p.Status.Phase)
+				return
+			}
 			log.Printf("[Event] %s is added, => %s\n", p.Name, p.Status.Phase)
-			projEvent <- struct{}{}
 		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			p := newObj.(*projectv1.Project)
-			log.Printf("[Event] %s is modified, => %s\n", p.Name, p.Status.Phase)
-			projEvent <- struct{}{}
-		},
-		DeleteFunc: func(obj interface{}) {
-			p := obj.(*projectv1.Project)
+		UpdateFunc: eventhandler.DiffingUpdateFunc(func(_, p *projectv1.Project, changes eventhandler.ChangeSet) {
+			log.Printf("[Event] %s is modified: %s\n", p.Name, changes)
+		}),
+		DeleteFunc: func(p *projectv1.Project) {
 			log.Printf("[Event] %s is deleted\n", p.Name)
-			projEvent <- struct{}{}
 		},
-	})
+	}, dispatcherOpts)
+	informer.AddEventHandler(dispatcher)
+	go dispatcher.Run(ctx)
+	defer func() {
+		log.Printf("Dispatcher metrics: %s", dispatcher.Metrics())
+	}()
 
 	log.Println("Starting informers")
 	go factory.Start(ctx.Done())
@@ -85,13 +71,6 @@ func main() {
 	}
 
 	// Ctrl-C will stop this program
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Application will shut down")
-			return
-		case <-projEvent:
-			continue
-		}
-	}
+	<-ctx.Done()
+	log.Println("Application will shut down")
 }
@@ -4,113 +4,59 @@ import (
 	"context"
 	"flag"
 	"log"
-	"path/filepath"
 	"time"
 
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	"github.com/fminamot/openshift-clientgo-demo/internal/controller"
+	"github.com/fminamot/openshift-clientgo-demo/internal/lifecycle"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
 	projectv1 "github.com/openshift/api/project/v1"
-	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
 	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
 	projectlisters "github.com/openshift/client-go/project/listers/project/v1"
 
-	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
-
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 	"k8s.io/client-go/util/workqueue"
 )
 
-func getProjectClientSet() (*projectclientset.Clientset, error) {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
-	}
-	flag.Parse()
-
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	clientset, err := projectclientset.NewForConfig(config)
-
-	return clientset, err
-}
-
-func printProject(workerIndex int, p *projectv1.Project) {
-	dn := p.Annotations["openshift.io/display-name"]
-	des := p.Annotations["openshift.io/description"]
-	status := p.Status.Phase
-	log.Printf("[worker:%d] name=%s, displayName=%s, description=%s, status=%s\n", workerIndex, p.Name, dn, des, status)
+func printProject(p *projectv1.Project) {
+	log.Printf("[worker] %s\n", printer.Summary(p))
 }
 
-func enqueue(obj interface{}, queue workqueue.TypedRateLimitingInterface[string]) {
-	key, err := cache.MetaNamespaceKeyFunc(obj)
-	if err != nil {
-		log.Println("enqueue error")
-		return
-	}
-	queue.Add(key)
-}
-
-func doBusinessLogic(workerIndex int, p *projectv1.Project) bool {
-	printProject(workerIndex, p)
+func doBusinessLogic(p *projectv1.Project) bool {
+	printProject(p)
 	return false // true
 }
 
-func processNextItem(workerIndex int, lister projectlisters.ProjectLister, queue workqueue.TypedRateLimitingInterface[string]) bool {
-	key, shutdown := queue.Get()
-	if shutdown {
-		return false
-	}
-
-	defer queue.Done(key)
-
-	p, err := lister.Get(key)
-	if errors.IsNotFound(err) {
-		log.Printf("%s not found in the cache\n", key)
-		queue.Forget(key)
-		return true
-	}
-	if err != nil {
-		log.Printf("Error getting the project: %s: %v\n", key, err)
-		queue.Forget(key)
-		return false
-	}
-
-	if ok := doBusinessLogic(workerIndex, p); ok {
-		queue.Forget(key)
-	} else {
-		queue.AddRateLimited(key)
-	}
-
-	return true
-}
+// newReconciler returns the function the controller calls for every key
+// taken from the workqueue. The business logic always fails here, so every
+// key is retried with exponential backoff until it is dropped after
+// -max-retries attempts.
+func newReconciler(lister projectlisters.ProjectLister) controller.ReconcileFunc {
+	return func(ctx context.Context, key string) (controller.Result, error) {
+		p, err := lister.Get(key)
+		if errors.IsNotFound(err) {
+			log.Printf("%s not found in the cache\n", key)
+			return controller.Result{}, nil
+		}
+		if err != nil {
+			return controller.Result{}, err
+		}
 
-func worker(ctx context.Context, workerIndex int, lister projectlisters.ProjectLister, queue workqueue.TypedRateLimitingInterface[string]) {
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("worker %d done for cancellation\n", workerIndex)
-			return
-		default:
-			if !processNextItem(workerIndex, lister, queue) {
-				log.Printf("worker %d done for queue shutdown\n", workerIndex)
-				return
-			}
+		if ok := doBusinessLogic(p); !ok {
+			return controller.Result{Requeue: true}, nil
 		}
+		return controller.Result{}, nil
 	}
 }
 
 func main() {
+	var maxRetries int
+	opts := clientconfig.NewOptions()
+	opts.AddFlags(flag.CommandLine)
+	flag.IntVar(&maxRetries, "max-retries", 5, "retries before a failing key is dropped, negative means no limit")
+	flag.Parse()
 
-	signalCtx := signals.SetupSignalHandler()
-	ctx, cancel := context.WithCancel(signalCtx)
-
-	clientset, err := getProjectClientSet()
+	clientset, err := opts.ProjectClientSet()
 	if err != nil {
 		log.Fatalf("Error creating project client: %v", err)
 	}
@@ -125,47 +71,31 @@ func main() {
 		60*time.Second,
 	)
 
-	queue := workqueue.NewTypedRateLimitingQueue[string](
-		rateLimiter,
-		// workqueue.DefaultTypedControllerRateLimiter[string](),
-	)
-
-	defer func() {
-		log.Println("Defer func called")
-		cancel()
-		queue.ShutDown()
-		factory.Shutdown()
-		log.Println("Informer stopped")
-	}()
-
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			enqueue(obj, queue)
-		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			enqueue(newObj, queue)
-		},
-		DeleteFunc: func(obj interface{}) {
-			enqueue(obj, queue)
-		},
+	c, err := controller.New(informer, newReconciler(lister), controller.Options[*projectv1.Project]{
+		Name:        "ratelimit",
+		Workers:     1,
+		RateLimiter: rateLimiter, // workqueue.DefaultTypedControllerRateLimiter[string](),
+		MaxRetries:  maxRetries,
 	})
+	if err != nil {
+		log.Fatalf("Error creating controller: %v", err)
+	}
 
-	log.Println("Starting informers")
-	go factory.Start(ctx.Done())
+	lm := lifecycle.New(10 * time.Second)
 
-	log.Printf("Waiting for cache synced")
-	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
-		log.Println("Failed to sync cache")
-	}
+	log.Println("Starting informers")
+	lm.AddInformerFactory("informer factory", factory)
 
-	workers := 1
+	// The controller drains its workqueue when the context is canceled;
+	// shutdown waits for it before the grace period ends.
 	log.Println("Ctrl-C will stop this program")
-	for i := 0; i < workers; i++ {
-		go worker(ctx, i, lister, queue)
-	}
-
-	<-ctx.Done()
-	log.Println("Application will shut down")
-	time.Sleep(5 * time.Second)
+	lm.Go("controller", func(ctx context.Context) {
+		if err := c.Run(ctx); err != nil {
+			log.Printf("Error running controller: %v", err)
+		}
+	})
 
+	if err := lm.Wait(); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
 }
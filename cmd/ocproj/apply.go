@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/manifest"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCommand(o *rootOptions) *cobra.Command {
+	var (
+		file     string
+		dryRun   bool
+		syncOpts project.SyncOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply -f FILE",
+		Short: "Make the cluster's projects match a manifest",
+		Long: `Create the projects declared in the manifest that do not exist yet and
+update the display name, description, labels and annotations of those that
+differ. With --prune, projects carrying the managed label that are no longer
+declared are deleted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			specs, err := manifest.Load(file)
+			if err != nil {
+				return err
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+			kubeClientset, err := o.kubeClient()
+			if err != nil {
+				return err
+			}
+
+			actions, err := project.PlanSync(cmd.Context(), clientset, specs, syncOpts)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(actions) == 0 {
+				fmt.Fprintln(out, "No changes")
+				return nil
+			}
+			if dryRun {
+				return printPreviews(cmd, project.DryRun(cmd.Context(), clientset, kubeClientset, actions))
+			}
+
+			syncOpts.OnAction = func(a project.Action) {
+				fmt.Fprintln(out, a)
+			}
+			applied := project.ApplySync(cmd.Context(), clientset, kubeClientset, actions, syncOpts)
+			if n := project.SyncFailures(applied); n > 0 {
+				return fmt.Errorf("%d of %d actions failed", n, len(applied))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "filename", "f", "projects.csv", "manifest file (.csv, .yaml, .yml or .json)")
+	cmd.Flags().StringVar(&syncOpts.ManagedSelector, "managed-selector", "app.kubernetes.io/managed-by=ocproj", "key=value label set on applied projects and required for pruning")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a diff of what would change without changing anything")
+	cmd.Flags().BoolVar(&syncOpts.Prune, "prune", false, "delete managed projects that are not in the manifest")
+	return cmd
+}
+
+func printPreviews(cmd *cobra.Command, previews []project.Preview) error {
+	failed := 0
+	for _, p := range previews {
+		fmt.Fprintln(cmd.OutOrStdout(), p)
+		if p.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d actions would fail", failed, len(previews))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/manifest"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+func newBulkCreateCommand(o *rootOptions) *cobra.Command {
+	var (
+		file         string
+		validateOnly bool
+		dryRun       bool
+		bulkOpts     project.BulkOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bulk-create -f FILE",
+		Short: "Create the projects listed in a CSV, YAML or JSON manifest",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			specs, err := manifest.Load(file)
+			if err != nil {
+				return err
+			}
+			if validateOnly {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %d project(s), no problems found\n", file, len(specs))
+				return nil
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+			kubeClientset, err := o.kubeClient()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				return previewBulkCreate(cmd, clientset, kubeClientset, specs)
+			}
+
+			bulkOpts.OnResult = func(r project.Result) {
+				fmt.Fprintln(out, r)
+			}
+			summary := project.BulkCreate(cmd.Context(), clientset, kubeClientset, specs, bulkOpts)
+			fmt.Fprintln(out, summary)
+
+			return summary.Err()
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "filename", "f", "projects.csv", "manifest file (.csv, .yaml, .yml or .json)")
+	cmd.Flags().BoolVar(&validateOnly, "validate-only", false, "report all problems in the file without contacting the cluster")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a diff of the projects that would be created without creating them")
+	cmd.Flags().IntVar(&bulkOpts.Concurrency, "concurrency", 4, "number of projects created in parallel")
+	cmd.Flags().DurationVar(&bulkOpts.Timeout, "request-timeout", 30*time.Second, "timeout of each project creation, 0 means none")
+	cmd.Flags().BoolVar(&bulkOpts.Idempotent, "idempotent", true, "treat existing projects as success, report drift and continue past failures")
+	return cmd
+}
+
+// previewBulkCreate dry-runs the creation of missing projects. Existing
+// projects are never changed by bulk-create, so differences are only
+// reported as drift.
+func previewBulkCreate(cmd *cobra.Command, clientset projectclientset.Interface, kubeClientset kubernetes.Interface, specs []project.Spec) error {
+	actions, err := project.PlanSync(cmd.Context(), clientset, specs, project.SyncOptions{})
+	if err != nil {
+		return err
+	}
+
+	var creates []project.Action
+	for _, a := range actions {
+		if a.Type == project.ActionCreate {
+			creates = append(creates, a)
+			continue
+		}
+		if a.Type == project.ActionUpdate {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s would be reported as drifted: %s\n", a.Name, strings.Join(a.Changes, ", "))
+		}
+	}
+	return printPreviews(cmd, project.DryRun(cmd.Context(), clientset, kubeClientset, creates))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	ctrl "github.com/fminamot/openshift-clientgo-demo/internal/controller"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectcontroller"
+	projectv1 "github.com/openshift/api/project/v1"
+	projectinformers "github.com/openshift/client-go/project/informers/externalversions"
+	"github.com/spf13/cobra"
+)
+
+func newControllerCommand(o *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Short: "Run project controllers",
+	}
+
+	cmd.AddCommand(newControllerRunCommand(o))
+	return cmd
+}
+
+func newControllerRunCommand(o *rootOptions) *cobra.Command {
+	var (
+		workers    int
+		maxRetries int
+		nameRegex  string
+		selector   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the project display-name controller until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			predicates, err := projectcontroller.Predicates(nameRegex, selector)
+			if err != nil {
+				return err
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			factory := projectinformers.NewSharedInformerFactory(clientset, 0)
+			controller, err := projectcontroller.NewProjectController(clientset, factory.Project().V1().Projects(), ctrl.Options[*projectv1.Project]{
+				Workers:    workers,
+				MaxRetries: maxRetries,
+				Predicates: predicates,
+			})
+			if err != nil {
+				return err
+			}
+			defer factory.Shutdown()
+
+			factory.Start(ctx.Done())
+
+			return controller.Run(ctx)
+		},
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", 1, "number of reconcile workers")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", ctrl.DefaultMaxRetries, "retries before a failing project is dropped, negative means no limit")
+	cmd.Flags().StringVar(&nameRegex, "name-regex", "", "only reconcile projects whose name matches this regular expression")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "only reconcile projects matching this label selector")
+	return cmd
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCommand(o *rootOptions) *cobra.Command {
+	var (
+		spec        project.Spec
+		labels      string
+		annotations string
+		createOpts  project.CreateOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a project",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spec.Name = args[0]
+
+			var err error
+			if spec.Labels, err = project.ParseKeyValues(labels); err != nil {
+				return fmt.Errorf("--labels: %w", err)
+			}
+			if spec.Annotations, err = project.ParseKeyValues(annotations); err != nil {
+				return fmt.Errorf("--annotations: %w", err)
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+			kubeClientset, err := o.kubeClient()
+			if err != nil {
+				return err
+			}
+
+			p, err := project.Create(cmd.Context(), clientset, kubeClientset, spec, createOpts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s created\n", p.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&spec.DisplayName, "display-name", "", "display name of the project")
+	cmd.Flags().StringVar(&spec.Description, "description", "", "description of the project")
+	cmd.Flags().StringVar(&labels, "labels", "", "comma separated key=value labels to set on the project")
+	cmd.Flags().StringVar(&annotations, "annotations", "", "comma separated key=value annotations to set on the project")
+	cmd.Flags().BoolVar(&createOpts.WaitActive, "wait-active", false, "wait until the project phase is Active")
+	cmd.Flags().DurationVar(&createOpts.Timeout, "timeout", time.Minute, "how long --wait-active waits")
+	return cmd
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/manifest"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCommand(o *rootOptions) *cobra.Command {
+	var (
+		file       string
+		filter     project.Filter
+		deleteOpts project.DeleteOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete [NAME...] [-l SELECTOR] [-f FILE]",
+		Short: "Delete projects and wait until they are gone",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && file == "" && filter == (project.Filter{}) {
+				return errors.New("specify project names, a filter such as -l, or -f FILE")
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			names, err := deleteTargets(cmd, clientset, args, file, filter)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No projects to delete")
+				return nil
+			}
+
+			out := cmd.OutOrStdout()
+			deleteOpts.OnDeleted = func(name string) {
+				fmt.Fprintf(out, "%s deleted\n", name)
+			}
+			deleteOpts.OnStuck = func(t project.Terminating) {
+				fmt.Fprintln(out, t)
+			}
+			err = project.Delete(cmd.Context(), clientset, names, deleteOpts)
+
+			var notDeleted *project.NotDeletedError
+			if errors.As(err, &notDeleted) {
+				for _, t := range notDeleted.Remaining {
+					fmt.Fprintln(cmd.ErrOrStderr(), t)
+				}
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "delete the projects listed in a manifest file (.csv, .yaml, .yml or .json)")
+	cmd.Flags().BoolVar(&deleteOpts.Wait, "wait", true, "wait until the projects are gone")
+	cmd.Flags().DurationVar(&deleteOpts.Timeout, "timeout", 5*time.Minute, "how long to wait, 0 means no timeout")
+	cmd.Flags().DurationVar(&deleteOpts.StuckAfter, "stuck-after", project.DefaultStuckAfter, "report projects still terminating after this long")
+	addFilterFlags(cmd, &filter)
+	return cmd
+}
+
+// deleteTargets collects the names given as arguments, listed in file and
+// matched by filter, without duplicates.
+func deleteTargets(cmd *cobra.Command, clientset projectclientset.Interface, args []string, file string, filter project.Filter) ([]string, error) {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range args {
+		add(name)
+	}
+	if file != "" {
+		specs, err := manifest.Load(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			add(spec.Name)
+		}
+	}
+	if filter != (project.Filter{}) {
+		matcher, err := filter.Compile()
+		if err != nil {
+			return nil, err
+		}
+		projects, err := project.List(cmd.Context(), clientset, matcher)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			add(p.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventlog"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func newEventsCommand(o *rootOptions) *cobra.Command {
+	var (
+		output  string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events [NAME]",
+		Short: "Stream project events as JSON lines or logfmt records",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			writer, err := eventlog.NewWriter(cmd.OutOrStdout(), output)
+			if err != nil {
+				return err
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			opts := metav1.ListOptions{}
+			if len(args) == 1 {
+				opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", args[0]).String()
+			}
+
+			w := projectwatch.New(ctx, clientset, projectwatch.Options{ListOptions: opts})
+			defer w.Stop()
+
+			recorder := eventlog.NewRecorder()
+			for event := range w.ResultChan() {
+				proj, skip, err := projectwatch.Next(event)
+				if err != nil {
+					return err
+				}
+				if skip {
+					continue
+				}
+
+				if err := writer.Write(recorder.Record(event.Type, proj)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "record format: "+eventlog.Formats)
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "stop streaming after this long, 0 means no timeout")
+	return cmd
+}
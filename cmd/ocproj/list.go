@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectv1 "github.com/openshift/api/project/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newListCommand(o *rootOptions) *cobra.Command {
+	var (
+		output    string
+		chunkSize int64
+		filter    project.Filter
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List projects",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := printer.New(cmd.OutOrStdout(), output)
+			if err != nil {
+				return err
+			}
+			matcher, err := filter.Compile()
+			if err != nil {
+				return err
+			}
+
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			err = project.ListPages(cmd.Context(), clientset, matcher, chunkSize, func(page []projectv1.Project) error {
+				for i := range page {
+					if err := p.PrintProject(&page[i]); err != nil {
+						return err
+					}
+				}
+				if !p.Streaming() {
+					return nil
+				}
+				return p.Flush()
+			})
+			if err != nil {
+				return err
+			}
+			return p.Flush()
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format: "+printer.Formats)
+	cmd.Flags().Int64Var(&chunkSize, "chunk-size", project.DefaultPageSize, "number of projects requested per page, 0 disables paging; the project API may still return all projects at once")
+	addFilterFlags(cmd, &filter)
+	return cmd
+}
+
+// addFilterFlags registers the project filter flags on cmd, with -l as
+// shorthand for --selector.
+func addFilterFlags(cmd *cobra.Command, filter *project.Filter) {
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	filter.AddFlags(fs)
+	// pflag only records a shorthand when the flag is added, so each flag
+	// is converted and given its shorthand before AddFlag.
+	fs.VisitAll(func(f *flag.Flag) {
+		pf := pflag.PFlagFromGoFlag(f)
+		if pf.Name == "selector" {
+			pf.Shorthand = "l"
+		}
+		cmd.Flags().AddFlag(pf)
+	})
+}
@@ -0,0 +1,19 @@
+// Command ocproj manages OpenShift projects: creating them one by one or
+// from a manifest, listing, watching, waiting for and deleting them, and
+// running the project controller.
+package main
+
+import (
+	"os"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/exitcode"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+)
+
+func main() {
+	ctx := signals.SetupSignalHandler()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(exitcode.For(err))
+	}
+}
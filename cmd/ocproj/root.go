@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/clientconfig"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+// rootOptions holds the state shared by all subcommands.
+type rootOptions struct {
+	client *clientconfig.Options
+}
+
+func (o *rootOptions) projectClient() (projectclientset.Interface, error) {
+	return o.client.ProjectClientSet()
+}
+
+func (o *rootOptions) kubeClient() (kubernetes.Interface, error) {
+	return o.client.KubeClientSet()
+}
+
+func newRootCommand() *cobra.Command {
+	o := &rootOptions{client: clientconfig.NewOptions()}
+
+	cmd := &cobra.Command{
+		Use:          "ocproj",
+		Short:        "Manage OpenShift projects",
+		SilenceUsage: true,
+	}
+
+	fs := flag.NewFlagSet("ocproj", flag.ContinueOnError)
+	o.client.AddFlags(fs)
+	cmd.PersistentFlags().AddGoFlagSet(fs)
+
+	cmd.AddCommand(
+		newCreateCommand(o),
+		newBulkCreateCommand(o),
+		newApplyCommand(o),
+		newListCommand(o),
+		newWatchCommand(o),
+		newEventsCommand(o),
+		newWaitCommand(o),
+		newDeleteCommand(o),
+		newControllerCommand(o),
+	)
+	return cmd
+}
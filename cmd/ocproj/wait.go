@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectv1 "github.com/openshift/api/project/v1"
+	"github.com/spf13/cobra"
+)
+
+func newWaitCommand(o *rootOptions) *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait NAME...",
+		Short: "Wait until projects become Active",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			return project.WaitForActive(cmd.Context(), clientset, args, project.WaitOptions{
+				Timeout: timeout,
+				OnReady: func(p *projectv1.Project, ready, total int) {
+					fmt.Fprintf(out, "%s is ready (%d/%d)\n", p.Name, ready, total)
+				},
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "how long to wait")
+	return cmd
+}
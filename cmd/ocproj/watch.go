@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func newWatchCommand(o *rootOptions) *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "watch [NAME]",
+		Short: "Print project events until interrupted",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientset, err := o.projectClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			opts := metav1.ListOptions{}
+			if len(args) == 1 {
+				opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", args[0]).String()
+			}
+
+			w := projectwatch.New(ctx, clientset, projectwatch.Options{ListOptions: opts})
+			defer w.Stop()
+
+			out := cmd.OutOrStdout()
+			for event := range w.ResultChan() {
+				proj, skip, err := projectwatch.Next(event)
+				if err != nil {
+					return err
+				}
+				if skip {
+					continue
+				}
+
+				switch event.Type {
+				case watch.Added:
+					fmt.Fprintf(out, "[Event] %s is added (phase: %s)\n", proj.Name, proj.Status.Phase)
+				case watch.Modified:
+					fmt.Fprintf(out, "[Event] %s is modified (phase: %s)\n", proj.Name, proj.Status.Phase)
+				case watch.Deleted:
+					fmt.Fprintf(out, "[Event] %s is deleted\n", proj.Name)
+				}
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				fmt.Fprintln(out, "Timeout")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "stop watching after this long, 0 means no timeout")
+	return cmd
+}
@@ -0,0 +1,103 @@
+// Package clientconfig builds REST configs and OpenShift Project client-sets
+// from kubeconfig files, command line overrides or the in-cluster environment.
+package clientconfig
+
+import (
+	"flag"
+
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Options holds the connection settings shared by every demo and tool.
+// An empty Options loads $KUBECONFIG (merging multiple files), then
+// ~/.kube/config, and falls back to the in-cluster config.
+type Options struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+	Server     string
+	Token      string
+
+	// QPS and Burst limit client-side request rates. Zero keeps the
+	// client-go defaults.
+	QPS   float64
+	Burst int
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// AddFlags registers the connection flags on fs. The caller owns fs and is
+// responsible for parsing it.
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "(optional) path to the kubeconfig file, overrides $KUBECONFIG")
+	fs.StringVar(&o.Context, "context", o.Context, "(optional) name of the kubeconfig context to use")
+	fs.StringVar(&o.Namespace, "namespace", o.Namespace, "(optional) namespace to use instead of the one in the kubeconfig context")
+	fs.StringVar(&o.Server, "server", o.Server, "(optional) address and port of the API server")
+	fs.StringVar(&o.Token, "token", o.Token, "(optional) bearer token for authentication to the API server")
+	fs.Float64Var(&o.QPS, "qps", o.QPS, "(optional) maximum queries per second to the API server, 0 uses the client default")
+	fs.IntVar(&o.Burst, "burst", o.Burst, "(optional) maximum burst of queries to the API server, 0 uses the client default")
+}
+
+// ClientConfig returns the merged, not yet resolved client configuration.
+func (o *Options) ClientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if o.Kubeconfig != "" {
+		rules.ExplicitPath = o.Kubeconfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: o.Context,
+	}
+	overrides.Context.Namespace = o.Namespace
+	overrides.ClusterInfo.Server = o.Server
+	overrides.AuthInfo.Token = o.Token
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+func (o *Options) RESTConfig() (*rest.Config, error) {
+	config, err := o.ClientConfig().ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.QPS > 0 {
+		config.QPS = float32(o.QPS)
+	}
+	if o.Burst > 0 {
+		config.Burst = o.Burst
+	}
+	return config, nil
+}
+
+// CurrentNamespace returns the namespace selected by --namespace or by the
+// current kubeconfig context.
+func (o *Options) CurrentNamespace() (string, error) {
+	ns, _, err := o.ClientConfig().Namespace()
+	return ns, err
+}
+
+func (o *Options) ProjectClientSet() (projectclientset.Interface, error) {
+	config, err := o.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return projectclientset.NewForConfig(config)
+}
+
+// KubeClientSet returns a core Kubernetes client-set, used where the Project
+// API does not allow an operation, e.g. changing namespace labels.
+func (o *Options) KubeClientSet() (kubernetes.Interface, error) {
+	config, err := o.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
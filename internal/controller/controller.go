@@ -0,0 +1,201 @@
+// Package controller runs reconcile loops for objects watched by a shared
+// informer, so a new controller only has to provide a reconcile function.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventhandler"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// DefaultMaxRetries is used when Options.MaxRetries is zero.
+const DefaultMaxRetries = 10
+
+// Result tells the controller what to do with a key after a reconcile.
+//
+// The key is handled as follows:
+//   - error returned: retried with the rate limiter's backoff (AddRateLimited)
+//   - RequeueAfter > 0: retried after RequeueAfter, backoff reset (AddAfter)
+//   - Requeue: retried with the rate limiter's backoff (AddRateLimited)
+//   - otherwise: done, backoff reset (Forget)
+//
+// A key retried with backoff more than Options.MaxRetries times in a row is
+// dropped and logged as a poison key.
+type Result struct {
+	// Requeue retries the key with backoff without reporting an error,
+	// e.g. when the object was not ready yet.
+	Requeue bool
+	// RequeueAfter, if positive, reconciles the key again after this long.
+	// It takes precedence over Requeue.
+	RequeueAfter time.Duration
+}
+
+// ReconcileFunc brings the object identified by key to its desired state.
+type ReconcileFunc func(ctx context.Context, key string) (Result, error)
+
+// Options configures a Controller.
+type Options[T runtime.Object] struct {
+	// Name is used in log messages and names the workqueue.
+	Name string
+	// Workers is the number of keys reconciled in parallel. Default 1.
+	Workers int
+	// RateLimiter delays retries of failed keys. It defaults to
+	// workqueue.DefaultTypedControllerRateLimiter.
+	RateLimiter workqueue.TypedRateLimiter[string]
+	// MaxRetries is how often a key is retried with backoff before it is
+	// dropped. Zero means DefaultMaxRetries, negative means no limit.
+	MaxRetries int
+	// Predicates select the informer events that enqueue a key.
+	Predicates []eventhandler.Predicate[T]
+}
+
+// Controller feeds the keys of objects of type T from an informer into a
+// rate limited workqueue and reconciles them.
+type Controller[T runtime.Object] struct {
+	name       string
+	workers    int
+	maxRetries int
+	informer   cache.SharedIndexInformer
+	reconcile  ReconcileFunc
+	queue      workqueue.TypedRateLimitingInterface[string]
+}
+
+// New returns a controller for informer and registers its event handler.
+// The informer must be started separately, e.g. by its factory.
+func New[T runtime.Object](informer cache.SharedIndexInformer, reconcile ReconcileFunc, opts Options[T]) (*Controller[T], error) {
+	if opts.Name == "" {
+		opts.Name = "controller"
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.RateLimiter == nil {
+		opts.RateLimiter = workqueue.DefaultTypedControllerRateLimiter[string]()
+	}
+
+	c := &Controller[T]{
+		name:       opts.Name,
+		workers:    opts.Workers,
+		maxRetries: opts.MaxRetries,
+		informer:   informer,
+		reconcile:  reconcile,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(opts.RateLimiter, workqueue.TypedRateLimitingQueueConfig[string]{
+			Name: opts.Name,
+		}),
+	}
+
+	_, err := informer.AddEventHandler(eventhandler.TypedEventHandler[T]{
+		AddFunc:    func(obj T, _ bool) { c.Enqueue(obj) },
+		UpdateFunc: func(_, newObj T) { c.Enqueue(newObj) },
+		DeleteFunc: func(obj T) { c.Enqueue(obj) },
+		Predicates: opts.Predicates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: adding event handler: %w", opts.Name, err)
+	}
+	return c, nil
+}
+
+// Enqueue adds the key of obj to the queue. Tombstones are accepted.
+func (c *Controller[T]) Enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Printf("[%s] Error getting key of %T: %v", c.name, obj, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run waits for the informer cache to sync, then reconciles keys until ctx
+// is done. On return the queue is shut down, after the keys being
+// reconciled have finished.
+func (c *Controller[T]) Run(ctx context.Context) error {
+	defer c.queue.ShutDown()
+
+	log.Printf("[%s] Waiting for cache sync", c.name)
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
+		return fmt.Errorf("%s: failed to sync cache", c.name)
+	}
+
+	log.Printf("[%s] Starting %d worker(s)", c.name, c.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c.processNextItem(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	log.Printf("[%s] Shutting down, draining in-flight keys", c.name)
+	c.queue.ShutDownWithDrain()
+	wg.Wait()
+	return nil
+}
+
+func (c *Controller[T]) processNextItem(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	result, err := c.reconcileHandler(ctx, key)
+	switch {
+	case err != nil:
+		c.retry(key, err)
+	case result.RequeueAfter > 0:
+		c.queue.Forget(key)
+		c.queue.AddAfter(key, result.RequeueAfter)
+	case result.Requeue:
+		c.retry(key, nil)
+	default:
+		c.queue.Forget(key)
+	}
+	return true
+}
+
+// retry requeues key with backoff, or drops it once it has been retried
+// maxRetries times.
+func (c *Controller[T]) retry(key string, err error) {
+	retries := c.queue.NumRequeues(key)
+	if c.maxRetries >= 0 && retries >= c.maxRetries {
+		reason := "requeue requested"
+		if err != nil {
+			reason = err.Error()
+		}
+		log.Printf("[%s] Dropping %s after %d retries: %s", c.name, key, retries, reason)
+		c.queue.Forget(key)
+		return
+	}
+
+	if err != nil {
+		log.Printf("[%s] Error reconciling %s, retrying: %v", c.name, key, err)
+	}
+	c.queue.AddRateLimited(key)
+}
+
+// reconcileHandler calls the reconcile function, turning a panic into an
+// error so that one bad key does not crash the controller.
+func (c *Controller[T]) reconcileHandler(ctx context.Context, key string) (result Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[%s] Panic reconciling %s: %v\n%s", c.name, key, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return c.reconcile(ctx, key)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	fcache "k8s.io/client-go/tools/cache/testing"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// recordingQueue records how the controller hands a key back to the
+// queue. Get and Done go to the embedded queue.
+type recordingQueue struct {
+	workqueue.TypedRateLimitingInterface[string]
+	numRequeues int
+	calls       []string
+}
+
+func (q *recordingQueue) AddRateLimited(key string) {
+	q.calls = append(q.calls, "AddRateLimited")
+}
+
+func (q *recordingQueue) AddAfter(key string, d time.Duration) {
+	q.calls = append(q.calls, "AddAfter "+d.String())
+}
+
+func (q *recordingQueue) Forget(key string) {
+	q.calls = append(q.calls, "Forget")
+}
+
+func (q *recordingQueue) NumRequeues(key string) int {
+	return q.numRequeues
+}
+
+func TestProcessNextItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRetries  int
+		numRequeues int
+		reconcile   ReconcileFunc
+		want        []string
+	}{
+		{
+			name:      "done",
+			reconcile: func(context.Context, string) (Result, error) { return Result{}, nil },
+			want:      []string{"Forget"},
+		},
+		{
+			name: "requeue after",
+			reconcile: func(context.Context, string) (Result, error) {
+				return Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+			},
+			want: []string{"Forget", "AddAfter 5s"},
+		},
+		{
+			name:      "requeue",
+			reconcile: func(context.Context, string) (Result, error) { return Result{Requeue: true}, nil },
+			want:      []string{"AddRateLimited"},
+		},
+		{
+			name:      "error",
+			reconcile: func(context.Context, string) (Result, error) { return Result{}, errors.New("boom") },
+			want:      []string{"AddRateLimited"},
+		},
+		{
+			name:      "panic",
+			reconcile: func(context.Context, string) (Result, error) { panic("boom") },
+			want:      []string{"AddRateLimited"},
+		},
+		{
+			name:        "error below max retries",
+			maxRetries:  3,
+			numRequeues: 2,
+			reconcile:   func(context.Context, string) (Result, error) { return Result{}, errors.New("boom") },
+			want:        []string{"AddRateLimited"},
+		},
+		{
+			name:        "error at max retries",
+			maxRetries:  3,
+			numRequeues: 3,
+			reconcile:   func(context.Context, string) (Result, error) { return Result{}, errors.New("boom") },
+			want:        []string{"Forget"},
+		},
+		{
+			name:        "requeue at max retries",
+			maxRetries:  3,
+			numRequeues: 3,
+			reconcile:   func(context.Context, string) (Result, error) { return Result{Requeue: true}, nil },
+			want:        []string{"Forget"},
+		},
+		{
+			name:        "no retry limit",
+			maxRetries:  -1,
+			numRequeues: 100,
+			reconcile:   func(context.Context, string) (Result, error) { return Result{}, errors.New("boom") },
+			want:        []string{"AddRateLimited"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			informer := cache.NewSharedIndexInformer(fcache.NewFakeControllerSource(), &projectv1.Project{}, 0, cache.Indexers{})
+			c, err := New(informer, tt.reconcile, Options[*projectv1.Project]{Name: "test", MaxRetries: tt.maxRetries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := &recordingQueue{TypedRateLimitingInterface: c.queue, numRequeues: tt.numRequeues}
+			c.queue = q
+			defer q.ShutDown()
+
+			q.Add("myproject")
+			if !c.processNextItem(context.Background()) {
+				t.Fatal("processNextItem() = false; want true")
+			}
+			if !reflect.DeepEqual(q.calls, tt.want) {
+				t.Errorf("queue calls = %v; want %v", q.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReconcilesInformerEvents(t *testing.T) {
+	source := fcache.NewFakeControllerSource()
+	informer := cache.NewSharedIndexInformer(source, &projectv1.Project{}, 0, cache.Indexers{})
+
+	keys := make(chan string, 1)
+	c, err := New(informer, func(_ context.Context, key string) (Result, error) {
+		keys <- key
+		return Result{}, nil
+	}, Options[*projectv1.Project]{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informer.Run(ctx.Done())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	source.Add(&projectv1.Project{ObjectMeta: metav1.ObjectMeta{Name: "myproject"}})
+	select {
+	case key := <-keys:
+		if key != "myproject" {
+			t.Errorf("reconciled %q; want myproject", key)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("key was not reconciled")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() = %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+// Package demo holds the setup shared by the numbered demo programs.
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/manifest"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CreateProjectsFromCSV creates the projects listed in csvFile, printing
+// each result, and returns the names of the projects that exist
+// afterwards. Projects that already exist are kept and compared with the
+// file. kube applies the labels and annotations columns.
+func CreateProjectsFromCSV(ctx context.Context, clientset projectclientset.Interface, kube kubernetes.Interface, csvFile string) ([]string, error) {
+	specs, err := manifest.ReadCSV(csvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	summary := project.BulkCreate(ctx, clientset, kube, specs, project.BulkOptions{
+		Idempotent: true,
+		OnResult: func(r project.Result) {
+			fmt.Println(r)
+			if r.Outcome != project.Failed {
+				names = append(names, r.Spec.Name)
+			}
+		},
+	})
+	fmt.Println(summary)
+
+	return names, summary.Err()
+}
@@ -0,0 +1,175 @@
+// Package eventhandler provides informer event handlers for projects.
+package eventhandler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	projectv1 "github.com/openshift/api/project/v1"
+)
+
+// Fields reported in a Change.
+const (
+	FieldLabel      = "label"
+	FieldAnnotation = "annotation"
+	FieldFinalizer  = "finalizer"
+	FieldPhase      = "phase"
+)
+
+// ChangeOp tells whether a Change added, removed or modified an entry.
+type ChangeOp string
+
+const (
+	ChangeAdded    ChangeOp = "added"
+	ChangeRemoved  ChangeOp = "removed"
+	ChangeModified ChangeOp = "modified"
+)
+
+// Change is one difference between two versions of a project. Op tells
+// which of Old and New are present, since a label or annotation can be
+// set to an empty value. Key is the label or annotation key or the
+// finalizer name, and is empty for the phase.
+type Change struct {
+	Field string   `json:"field"`
+	Op    ChangeOp `json:"op"`
+	Key   string   `json:"key,omitempty"`
+	Old   string   `json:"old,omitempty"`
+	New   string   `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	if c.Field == FieldPhase {
+		return fmt.Sprintf("phase: %s -> %s", quoteEmpty(c.Old), quoteEmpty(c.New))
+	}
+	if c.Field == FieldFinalizer {
+		if c.Op == ChangeRemoved {
+			return "-finalizer " + c.Key
+		}
+		return "+finalizer " + c.Key
+	}
+
+	switch c.Op {
+	case ChangeAdded:
+		return fmt.Sprintf("+%s %s=%s", c.Field, c.Key, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("-%s %s=%s", c.Field, c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~%s %s: %s -> %s", c.Field, c.Key, quoteEmpty(c.Old), quoteEmpty(c.New))
+	}
+}
+
+// quoteEmpty shows an empty value as "" so a change from or to an empty
+// value stays readable.
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
+
+// ChangeSet is the list of changes between two versions of a project,
+// ordered by field and key.
+type ChangeSet []Change
+
+func (cs ChangeSet) String() string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = c.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// Has reports whether cs contains a change to field.
+func (cs ChangeSet) Has(field string) bool {
+	for _, c := range cs {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares the labels, annotations, finalizers and phase of two
+// versions of a project. Everything else, including the resourceVersion,
+// is ignored, so a resync or a resourceVersion-only update yields an empty
+// change set.
+func Diff(oldP, newP *projectv1.Project) ChangeSet {
+	var cs ChangeSet
+	cs = append(cs, diffMaps(FieldLabel, oldP.Labels, newP.Labels)...)
+	cs = append(cs, diffMaps(FieldAnnotation, oldP.Annotations, newP.Annotations)...)
+	cs = append(cs, diffSets(FieldFinalizer, finalizers(oldP), finalizers(newP))...)
+	if oldP.Status.Phase != newP.Status.Phase {
+		cs = append(cs, Change{Field: FieldPhase, Op: ChangeModified, Old: string(oldP.Status.Phase), New: string(newP.Status.Phase)})
+	}
+	return cs
+}
+
+func diffMaps(field string, before, after map[string]string) []Change {
+	var changes []Change
+	for _, k := range unionKeys(before, after) {
+		oldV, inBefore := before[k]
+		newV, inAfter := after[k]
+		switch {
+		case inBefore && !inAfter:
+			changes = append(changes, Change{Field: field, Op: ChangeRemoved, Key: k, Old: oldV})
+		case !inBefore && inAfter:
+			changes = append(changes, Change{Field: field, Op: ChangeAdded, Key: k, New: newV})
+		case oldV != newV:
+			changes = append(changes, Change{Field: field, Op: ChangeModified, Key: k, Old: oldV, New: newV})
+		}
+	}
+	return changes
+}
+
+func diffSets(field string, before, after map[string]string) []Change {
+	var changes []Change
+	for _, k := range unionKeys(before, after) {
+		_, inBefore := before[k]
+		_, inAfter := after[k]
+		switch {
+		case inBefore && !inAfter:
+			changes = append(changes, Change{Field: field, Op: ChangeRemoved, Key: k, Old: k})
+		case !inBefore && inAfter:
+			changes = append(changes, Change{Field: field, Op: ChangeAdded, Key: k, New: k})
+		}
+	}
+	return changes
+}
+
+// finalizers returns the spec and metadata finalizers of p as a set.
+func finalizers(p *projectv1.Project) map[string]string {
+	set := map[string]string{}
+	for _, f := range p.Spec.Finalizers {
+		set[string(f)] = string(f)
+	}
+	for _, f := range p.Finalizers {
+		set[f] = f
+	}
+	return set
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DiffingUpdateFunc returns a ProjectHandlerFuncs.UpdateFunc that
+// computes the change set between the old and new project and calls fn
+// with it. Updates without changes are suppressed.
+func DiffingUpdateFunc(fn func(oldP, newP *projectv1.Project, changes ChangeSet)) func(oldP, newP *projectv1.Project) {
+	return func(oldP, newP *projectv1.Project) {
+		if changes := Diff(oldP, newP); len(changes) > 0 {
+			fn(oldP, newP, changes)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package eventhandler
+
+import (
+	"reflect"
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(oldP, newP *projectv1.Project)
+		want   ChangeSet
+		str    string
+	}{
+		{
+			name:   "no change",
+			mutate: func(oldP, newP *projectv1.Project) { newP.ResourceVersion = "2" },
+		},
+		{
+			name:   "label added",
+			mutate: func(oldP, newP *projectv1.Project) { newP.Labels = map[string]string{"team": "a"} },
+			want:   ChangeSet{{Field: FieldLabel, Op: ChangeAdded, Key: "team", New: "a"}},
+			str:    "+label team=a",
+		},
+		{
+			name:   "label removed",
+			mutate: func(oldP, newP *projectv1.Project) { oldP.Labels = map[string]string{"team": "a"} },
+			want:   ChangeSet{{Field: FieldLabel, Op: ChangeRemoved, Key: "team", Old: "a"}},
+			str:    "-label team=a",
+		},
+		{
+			name: "label changed",
+			mutate: func(oldP, newP *projectv1.Project) {
+				oldP.Labels = map[string]string{"team": "a"}
+				newP.Labels = map[string]string{"team": "b"}
+			},
+			want: ChangeSet{{Field: FieldLabel, Op: ChangeModified, Key: "team", Old: "a", New: "b"}},
+			str:  "~label team: a -> b",
+		},
+		{
+			name:   "empty annotation added",
+			mutate: func(oldP, newP *projectv1.Project) { newP.Annotations = map[string]string{"note": ""} },
+			want:   ChangeSet{{Field: FieldAnnotation, Op: ChangeAdded, Key: "note"}},
+			str:    "+annotation note=",
+		},
+		{
+			name:   "empty annotation removed",
+			mutate: func(oldP, newP *projectv1.Project) { oldP.Annotations = map[string]string{"note": ""} },
+			want:   ChangeSet{{Field: FieldAnnotation, Op: ChangeRemoved, Key: "note"}},
+			str:    "-annotation note=",
+		},
+		{
+			name: "annotation set to empty",
+			mutate: func(oldP, newP *projectv1.Project) {
+				oldP.Annotations = map[string]string{"note": "x"}
+				newP.Annotations = map[string]string{"note": ""}
+			},
+			want: ChangeSet{{Field: FieldAnnotation, Op: ChangeModified, Key: "note", Old: "x"}},
+			str:  `~annotation note: x -> ""`,
+		},
+		{
+			name: "finalizers",
+			mutate: func(oldP, newP *projectv1.Project) {
+				oldP.Spec.Finalizers = []corev1.FinalizerName{"kubernetes"}
+				newP.Finalizers = []string{"example.com/cleanup"}
+			},
+			want: ChangeSet{
+				{Field: FieldFinalizer, Op: ChangeAdded, Key: "example.com/cleanup", New: "example.com/cleanup"},
+				{Field: FieldFinalizer, Op: ChangeRemoved, Key: "kubernetes", Old: "kubernetes"},
+			},
+			str: "+finalizer example.com/cleanup, -finalizer kubernetes",
+		},
+		{
+			name:   "phase",
+			mutate: func(oldP, newP *projectv1.Project) { newP.Status.Phase = corev1.NamespaceTerminating },
+			want:   ChangeSet{{Field: FieldPhase, Op: ChangeModified, Old: "Active", New: "Terminating"}},
+			str:    "phase: Active -> Terminating",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldP, newP := newProject("myproject"), newProject("myproject")
+			tt.mutate(oldP, newP)
+
+			got := Diff(oldP, newP)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v; want %#v", got, tt.want)
+			}
+			if s := got.String(); s != tt.str {
+				t.Errorf("Diff().String() = %q; want %q", s, tt.str)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package eventhandler
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// OverflowPolicy decides what a Dispatcher does with an event when its
+// buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block makes the informer wait until there is room in the buffer.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest buffered event.
+	DropOldest
+	// Coalesce merges an event into the buffered event for the same key,
+	// so at most one event per object is pending. An event for a new key
+	// that finds the buffer full discards the oldest buffered event, so
+	// the informer is never blocked, even by an initial list larger than
+	// the buffer.
+	Coalesce
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case Coalesce:
+		return "coalesce"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// ParseOverflowPolicy parses the names returned by OverflowPolicy.String.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	for _, p := range []OverflowPolicy{Block, DropOldest, Coalesce} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return Block, fmt.Errorf("unknown overflow policy %q, expected block, drop-oldest or coalesce", s)
+}
+
+// Set implements flag.Value.
+func (p *OverflowPolicy) Set(s string) error {
+	v, err := ParseOverflowPolicy(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// DefaultBufferSize is used when DispatcherOptions.BufferSize is not set.
+const DefaultBufferSize = 100
+
+// DispatcherOptions controls a Dispatcher.
+type DispatcherOptions struct {
+	BufferSize int
+	Policy     OverflowPolicy
+	// KeyFunc identifies objects for Coalesce. It defaults to
+	// cache.DeletionHandlingMetaNamespaceKeyFunc.
+	KeyFunc cache.KeyFunc
+}
+
+// AddFlags registers the buffer size and overflow policy flags on fs,
+// using the current values as defaults.
+func (o *DispatcherOptions) AddFlags(fs *flag.FlagSet) {
+	if o.BufferSize <= 0 {
+		o.BufferSize = DefaultBufferSize
+	}
+	fs.IntVar(&o.BufferSize, "buffer-size", o.BufferSize, "number of events buffered between the informer and the handlers")
+	fs.Var(&o.Policy, "overflow", "what to do when the buffer is full: block, drop-oldest or coalesce")
+}
+
+// Metrics is a snapshot of a Dispatcher's counters.
+type Metrics struct {
+	Depth     int
+	MaxDepth  int
+	Received  uint64
+	Delivered uint64
+	Dropped   uint64
+	Coalesced uint64
+}
+
+func (m Metrics) String() string {
+	return fmt.Sprintf("depth=%d max-depth=%d received=%d delivered=%d dropped=%d coalesced=%d",
+		m.Depth, m.MaxDepth, m.Received, m.Delivered, m.Dropped, m.Coalesced)
+}
+
+type eventKind int
+
+const (
+	addEvent eventKind = iota
+	updateEvent
+	deleteEvent
+)
+
+type event struct {
+	kind        eventKind
+	key         string
+	oldObj, obj interface{}
+	initialList bool
+}
+
+// merge folds next, a later event for the same key, into e. It returns
+// false when the two cancel out: an object that was added and deleted
+// before the handler saw it.
+func (e *event) merge(next *event) bool {
+	switch {
+	case next.kind == deleteEvent && e.kind == addEvent:
+		return false
+	case next.kind == deleteEvent:
+		e.kind, e.oldObj, e.obj = deleteEvent, nil, next.obj
+	case e.kind == addEvent:
+		e.obj = next.obj
+	case e.kind == updateEvent:
+		e.obj = next.obj
+	case e.kind == deleteEvent:
+		// Deleted and created again: the object was replaced.
+		e.kind, e.oldObj, e.obj = updateEvent, e.obj, next.obj
+	}
+	return true
+}
+
+// Dispatcher is a cache.ResourceEventHandler that buffers informer events
+// and delivers them to another handler on its own goroutine. A slow
+// handler only holds up the informer's notifications under Block, once
+// the buffer is full; DropOldest and Coalesce discard events instead,
+// which Metrics counts as dropped.
+type Dispatcher struct {
+	handler cache.ResourceEventHandler
+	opts    DispatcherOptions
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []*event
+	pending map[string]*event
+	stopped bool
+	metrics Metrics
+}
+
+var _ cache.ResourceEventHandler = &Dispatcher{}
+
+// NewDispatcher returns a dispatcher delivering to handler. Events are
+// only delivered while Run is running.
+func NewDispatcher(handler cache.ResourceEventHandler, opts DispatcherOptions) *Dispatcher {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+	if opts.KeyFunc == nil {
+		opts.KeyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
+	}
+
+	d := &Dispatcher{
+		handler: handler,
+		opts:    opts,
+		pending: map[string]*event{},
+	}
+	d.cond = sync.NewCond(&d.mu)
+	return d
+}
+
+func (d *Dispatcher) OnAdd(obj interface{}, isInInitialList bool) {
+	d.enqueue(&event{kind: addEvent, obj: obj, initialList: isInInitialList})
+}
+
+func (d *Dispatcher) OnUpdate(oldObj, newObj interface{}) {
+	d.enqueue(&event{kind: updateEvent, oldObj: oldObj, obj: newObj})
+}
+
+func (d *Dispatcher) OnDelete(obj interface{}) {
+	d.enqueue(&event{kind: deleteEvent, obj: obj})
+}
+
+func (d *Dispatcher) enqueue(e *event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.metrics.Received++
+	if d.opts.Policy == Coalesce {
+		key, err := d.opts.KeyFunc(e.obj)
+		if err == nil {
+			if p, ok := d.pending[key]; ok {
+				if !p.merge(e) {
+					d.remove(p)
+				}
+				d.metrics.Coalesced++
+				return
+			}
+			e.key = key
+		}
+	}
+
+	for len(d.queue) >= d.opts.BufferSize && !d.stopped {
+		if d.opts.Policy != Block {
+			d.pop()
+			d.metrics.Dropped++
+			continue
+		}
+		d.cond.Wait()
+	}
+	if d.stopped {
+		d.metrics.Dropped++
+		return
+	}
+
+	d.queue = append(d.queue, e)
+	if e.key != "" {
+		d.pending[e.key] = e
+	}
+	d.metrics.Depth = len(d.queue)
+	if d.metrics.Depth > d.metrics.MaxDepth {
+		d.metrics.MaxDepth = d.metrics.Depth
+	}
+	d.cond.Broadcast()
+}
+
+// pop removes the oldest event. d.mu must be held.
+func (d *Dispatcher) pop() *event {
+	e := d.queue[0]
+	d.queue[0] = nil
+	d.queue = d.queue[1:]
+	if e.key != "" && d.pending[e.key] == e {
+		delete(d.pending, e.key)
+	}
+	d.metrics.Depth = len(d.queue)
+	return e
+}
+
+// remove discards the buffered event e. d.mu must be held.
+func (d *Dispatcher) remove(e *event) {
+	for i, q := range d.queue {
+		if q == e {
+			copy(d.queue[i:], d.queue[i+1:])
+			d.queue[len(d.queue)-1] = nil
+			d.queue = d.queue[:len(d.queue)-1]
+			break
+		}
+	}
+	delete(d.pending, e.key)
+	d.metrics.Depth = len(d.queue)
+	d.cond.Broadcast()
+}
+
+// next waits for an event. It returns false once the dispatcher is stopped.
+func (d *Dispatcher) next() (*event, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for len(d.queue) == 0 && !d.stopped {
+		d.cond.Wait()
+	}
+	if d.stopped {
+		return nil, false
+	}
+	e := d.pop()
+	d.cond.Broadcast()
+	return e, true
+}
+
+// Run delivers events until ctx is done. Events still buffered then are
+// discarded and counted as dropped, and the informer is never blocked by
+// the dispatcher again.
+func (d *Dispatcher) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		d.mu.Lock()
+		d.stopped = true
+		d.metrics.Dropped += uint64(len(d.queue))
+		d.queue = nil
+		d.pending = map[string]*event{}
+		d.metrics.Depth = 0
+		d.cond.Broadcast()
+		d.mu.Unlock()
+	}()
+
+	for {
+		e, ok := d.next()
+		if !ok {
+			return
+		}
+
+		switch e.kind {
+		case addEvent:
+			d.handler.OnAdd(e.obj, e.initialList)
+		case updateEvent:
+			d.handler.OnUpdate(e.oldObj, e.obj)
+		case deleteEvent:
+			d.handler.OnDelete(e.obj)
+		}
+
+		d.mu.Lock()
+		d.metrics.Delivered++
+		d.mu.Unlock()
+	}
+}
+
+// Metrics returns the current counters.
+func (d *Dispatcher) Metrics() Metrics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.metrics
+}
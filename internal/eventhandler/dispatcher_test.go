@@ -0,0 +1,236 @@
+package eventhandler
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// recorder is a handler that records the events it receives as
+// "<kind> <name> <resourceVersion>". If gate is set, each event waits for
+// it first.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	gate   chan struct{}
+}
+
+func (r *recorder) record(kind string, obj interface{}) {
+	if r.gate != nil {
+		<-r.gate
+	}
+	p, _ := ProjectFromObject(obj)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, kind+" "+p.Name+" "+p.ResourceVersion)
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func (r *recorder) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { r.record("add", obj) },
+		UpdateFunc: func(_, obj interface{}) { r.record("update", obj) },
+		DeleteFunc: func(obj interface{}) { r.record("delete", obj) },
+	}
+}
+
+func version(name, rv string) *projectv1.Project {
+	p := newProject(name)
+	p.ResourceVersion = rv
+	return p
+}
+
+// runDispatcher starts d and returns a function that stops it and waits
+// for Run to return.
+func runDispatcher(t *testing.T, d *Dispatcher) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestDispatcherPolicies(t *testing.T) {
+	a1, a2, a3 := version("a", "1"), version("a", "2"), version("a", "3")
+	b1, c1 := version("b", "1"), version("c", "1")
+
+	tests := []struct {
+		name    string
+		policy  OverflowPolicy
+		size    int
+		enqueue func(d *Dispatcher)
+		want    []string
+		metrics Metrics
+	}{
+		{
+			name:   "drop-oldest",
+			policy: DropOldest,
+			size:   2,
+			enqueue: func(d *Dispatcher) {
+				d.OnAdd(a1, false)
+				d.OnAdd(b1, false)
+				d.OnAdd(c1, false)
+			},
+			want:    []string{"add b 1", "add c 1"},
+			metrics: Metrics{MaxDepth: 2, Received: 3, Delivered: 2, Dropped: 1},
+		},
+		{
+			name:   "coalesce add and updates",
+			policy: Coalesce,
+			enqueue: func(d *Dispatcher) {
+				d.OnAdd(a1, true)
+				d.OnUpdate(a1, a2)
+				d.OnUpdate(a2, a3)
+			},
+			want:    []string{"add a 3"},
+			metrics: Metrics{MaxDepth: 1, Received: 3, Delivered: 1, Coalesced: 2},
+		},
+		{
+			name:   "coalesce update and delete",
+			policy: Coalesce,
+			enqueue: func(d *Dispatcher) {
+				d.OnUpdate(a1, a2)
+				d.OnDelete(a3)
+			},
+			want:    []string{"delete a 3"},
+			metrics: Metrics{MaxDepth: 1, Received: 2, Delivered: 1, Coalesced: 1},
+		},
+		{
+			name:   "coalesce add and delete cancel out",
+			policy: Coalesce,
+			enqueue: func(d *Dispatcher) {
+				d.OnAdd(a1, false)
+				d.OnAdd(b1, false)
+				d.OnDelete(a1)
+			},
+			want:    []string{"add b 1"},
+			metrics: Metrics{MaxDepth: 2, Received: 3, Delivered: 1, Coalesced: 1},
+		},
+		{
+			name:   "coalesce delete and add",
+			policy: Coalesce,
+			enqueue: func(d *Dispatcher) {
+				d.OnDelete(a1)
+				d.OnAdd(a2, false)
+			},
+			want:    []string{"update a 2"},
+			metrics: Metrics{MaxDepth: 1, Received: 2, Delivered: 1, Coalesced: 1},
+		},
+		{
+			name:   "coalesce is bounded",
+			policy: Coalesce,
+			size:   2,
+			enqueue: func(d *Dispatcher) {
+				d.OnAdd(a1, true)
+				d.OnAdd(b1, true)
+				d.OnAdd(c1, true)
+				d.OnUpdate(c1, version("c", "2"))
+			},
+			want:    []string{"add b 1", "add c 2"},
+			metrics: Metrics{MaxDepth: 2, Received: 4, Delivered: 2, Dropped: 1, Coalesced: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			d := NewDispatcher(r.handler(), DispatcherOptions{BufferSize: tt.size, Policy: tt.policy})
+
+			// Events are buffered until Run starts, so the policy sees them
+			// all before any is delivered.
+			tt.enqueue(d)
+			stop := runDispatcher(t, d)
+			defer stop()
+
+			waitFor(t, func() bool { return d.Metrics().Delivered == uint64(len(tt.want)) })
+			if got := r.got(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %v; want %v", got, tt.want)
+			}
+			if got := d.Metrics(); got != tt.metrics {
+				t.Errorf("metrics = %v; want %v", got, tt.metrics)
+			}
+		})
+	}
+}
+
+func TestDispatcherBlock(t *testing.T) {
+	r := &recorder{}
+	d := NewDispatcher(r.handler(), DispatcherOptions{BufferSize: 1, Policy: Block})
+
+	d.OnAdd(version("a", "1"), false)
+	enqueued := make(chan struct{})
+	go func() {
+		defer close(enqueued)
+		d.OnAdd(version("b", "1"), false)
+	}()
+
+	select {
+	case <-enqueued:
+		t.Fatal("OnAdd returned although the buffer was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	stop := runDispatcher(t, d)
+	defer stop()
+	<-enqueued
+
+	waitFor(t, func() bool { return d.Metrics().Delivered == 2 })
+	if got, want := r.got(), []string{"add a 1", "add b 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v; want %v", got, want)
+	}
+	if m := d.Metrics(); m.Dropped != 0 || m.MaxDepth != 1 {
+		t.Errorf("metrics = %v; want nothing dropped and max depth 1", m)
+	}
+}
+
+func TestDispatcherStop(t *testing.T) {
+	r := &recorder{gate: make(chan struct{})}
+	d := NewDispatcher(r.handler(), DispatcherOptions{BufferSize: 1, Policy: Block})
+	stop := runDispatcher(t, d)
+
+	// a is taken by the handler, which waits on the gate, b fills the
+	// buffer and c blocks.
+	d.OnAdd(version("a", "1"), false)
+	waitFor(t, func() bool { return d.Metrics().Depth == 0 })
+	d.OnAdd(version("b", "1"), false)
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		d.OnAdd(version("c", "1"), false)
+	}()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		stop()
+	}()
+
+	// Stopping releases the blocked informer before the handler returns.
+	<-blocked
+	d.OnAdd(version("d", "1"), false)
+	close(r.gate)
+	<-stopped
+
+	if got, want := r.got(), []string{"add a 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v; want %v", got, want)
+	}
+	want := Metrics{MaxDepth: 1, Received: 4, Delivered: 1, Dropped: 3}
+	if got := d.Metrics(); got != want {
+		t.Errorf("metrics = %v; want %v", got, want)
+	}
+}
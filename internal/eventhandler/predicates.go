@@ -0,0 +1,179 @@
+package eventhandler
+
+import (
+	"regexp"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Object is an API object with metadata, such as *projectv1.Project.
+type Object interface {
+	runtime.Object
+	metav1.Object
+}
+
+// AnnotationChanged accepts updates that change any of the given annotation
+// keys, or any annotation when no keys are given. Adds and deletes pass.
+func AnnotationChanged[T Object](keys ...string) Predicate[T] {
+	return PredicateFuncs[T]{
+		UpdateFunc: func(oldObj, newObj T) bool {
+			return mapChanged(oldObj.GetAnnotations(), newObj.GetAnnotations(), keys)
+		},
+	}
+}
+
+// LabelChanged accepts updates that change any of the given label keys, or
+// any label when no keys are given. Adds and deletes pass.
+func LabelChanged[T Object](keys ...string) Predicate[T] {
+	return PredicateFuncs[T]{
+		UpdateFunc: func(oldObj, newObj T) bool {
+			return mapChanged(oldObj.GetLabels(), newObj.GetLabels(), keys)
+		},
+	}
+}
+
+func mapChanged(before, after map[string]string, keys []string) bool {
+	if len(keys) == 0 {
+		if len(before) != len(after) {
+			return true
+		}
+		keys = unionKeys(before, after)
+	}
+	for _, k := range keys {
+		old, inBefore := before[k]
+		cur, inAfter := after[k]
+		if inBefore != inAfter || old != cur {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerationChanged accepts updates that change metadata.generation, that
+// is, changes to the spec rather than to metadata or status. Adds and
+// deletes pass. It is meant for types such as Deployments: namespaces, and
+// so projects, never bump their generation, and every project update
+// would be rejected.
+func GenerationChanged[T Object]() Predicate[T] {
+	return PredicateFuncs[T]{
+		UpdateFunc: func(oldObj, newObj T) bool {
+			return oldObj.GetGeneration() != newObj.GetGeneration()
+		},
+	}
+}
+
+// PhaseTransition accepts project updates that change the phase, into one
+// of the given phases if any are given. Adds and deletes pass.
+func PhaseTransition(to ...corev1.NamespacePhase) Predicate[*projectv1.Project] {
+	return PredicateFuncs[*projectv1.Project]{
+		UpdateFunc: func(oldP, newP *projectv1.Project) bool {
+			if oldP.Status.Phase == newP.Status.Phase {
+				return false
+			}
+			if len(to) == 0 {
+				return true
+			}
+			for _, phase := range to {
+				if newP.Status.Phase == phase {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// NameMatches accepts events for objects whose name matches re.
+func NameMatches[T Object](re *regexp.Regexp) Predicate[T] {
+	match := func(obj T) bool { return re.MatchString(obj.GetName()) }
+	return PredicateFuncs[T]{
+		AddFunc:    match,
+		UpdateFunc: func(_, newObj T) bool { return match(newObj) },
+		DeleteFunc: match,
+	}
+}
+
+// LabelSelector accepts events for objects whose labels match selector.
+// Updates pass when either version matches, so an object that stops
+// matching is still seen once.
+func LabelSelector[T Object](selector labels.Selector) Predicate[T] {
+	match := func(obj T) bool { return selector.Matches(labels.Set(obj.GetLabels())) }
+	return PredicateFuncs[T]{
+		AddFunc:    match,
+		UpdateFunc: func(oldObj, newObj T) bool { return match(oldObj) || match(newObj) },
+		DeleteFunc: match,
+	}
+}
+
+// And accepts events that every predicate accepts.
+func And[T any](predicates ...Predicate[T]) Predicate[T] {
+	return PredicateFuncs[T]{
+		AddFunc: func(obj T) bool {
+			for _, p := range predicates {
+				if !p.Add(obj) {
+					return false
+				}
+			}
+			return true
+		},
+		UpdateFunc: func(oldObj, newObj T) bool {
+			for _, p := range predicates {
+				if !p.Update(oldObj, newObj) {
+					return false
+				}
+			}
+			return true
+		},
+		DeleteFunc: func(obj T) bool {
+			for _, p := range predicates {
+				if !p.Delete(obj) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// Or accepts events that at least one predicate accepts.
+func Or[T any](predicates ...Predicate[T]) Predicate[T] {
+	return PredicateFuncs[T]{
+		AddFunc: func(obj T) bool {
+			for _, p := range predicates {
+				if p.Add(obj) {
+					return true
+				}
+			}
+			return false
+		},
+		UpdateFunc: func(oldObj, newObj T) bool {
+			for _, p := range predicates {
+				if p.Update(oldObj, newObj) {
+					return true
+				}
+			}
+			return false
+		},
+		DeleteFunc: func(obj T) bool {
+			for _, p := range predicates {
+				if p.Delete(obj) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Not accepts the events that p rejects.
+func Not[T any](p Predicate[T]) Predicate[T] {
+	return PredicateFuncs[T]{
+		AddFunc:    func(obj T) bool { return !p.Add(obj) },
+		UpdateFunc: func(oldObj, newObj T) bool { return !p.Update(oldObj, newObj) },
+		DeleteFunc: func(obj T) bool { return !p.Delete(obj) },
+	}
+}
@@ -0,0 +1,97 @@
+package eventhandler
+
+import (
+	"regexp"
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func withMeta(labels, annotations map[string]string) *projectv1.Project {
+	p := newProject("myproject")
+	p.Labels, p.Annotations = labels, annotations
+	return p
+}
+
+func TestPredicateUpdates(t *testing.T) {
+	accept := PredicateFuncs[*projectv1.Project]{}
+	reject := PredicateFuncs[*projectv1.Project]{
+		AddFunc:    func(*projectv1.Project) bool { return false },
+		UpdateFunc: func(_, _ *projectv1.Project) bool { return false },
+		DeleteFunc: func(*projectv1.Project) bool { return false },
+	}
+	terminating := newProject("myproject")
+	terminating.Status.Phase = corev1.NamespaceTerminating
+
+	tests := []struct {
+		name     string
+		p        Predicate[*projectv1.Project]
+		old, new *projectv1.Project
+		want     bool
+	}{
+		{"annotation added", AnnotationChanged[*projectv1.Project](), withMeta(nil, nil), withMeta(nil, map[string]string{"a": "1"}), true},
+		{"annotation unchanged", AnnotationChanged[*projectv1.Project](), withMeta(nil, map[string]string{"a": "1"}), withMeta(nil, map[string]string{"a": "1"}), false},
+		{"empty annotation added", AnnotationChanged[*projectv1.Project]("a"), withMeta(nil, nil), withMeta(nil, map[string]string{"a": ""}), true},
+		{"other annotation changed", AnnotationChanged[*projectv1.Project]("a"), withMeta(nil, map[string]string{"b": "1"}), withMeta(nil, map[string]string{"b": "2"}), false},
+		{"label changed", LabelChanged[*projectv1.Project]("team"), withMeta(map[string]string{"team": "a"}, nil), withMeta(map[string]string{"team": "b"}, nil), true},
+		{"label removed", LabelChanged[*projectv1.Project](), withMeta(map[string]string{"team": "a"}, nil), withMeta(nil, nil), true},
+		{"labels unchanged", LabelChanged[*projectv1.Project](), withMeta(map[string]string{"team": "a"}, nil), withMeta(map[string]string{"team": "a"}, nil), false},
+		{"phase changed", PhaseTransition(), newProject("myproject"), terminating, true},
+		{"phase changed to other", PhaseTransition(corev1.NamespaceActive), newProject("myproject"), terminating, false},
+		{"phase unchanged", PhaseTransition(), newProject("myproject"), newProject("myproject"), false},
+		{"selector matches old only", LabelSelector[*projectv1.Project](labels.SelectorFromSet(labels.Set{"team": "a"})), withMeta(map[string]string{"team": "a"}, nil), withMeta(nil, nil), true},
+		{"selector matches neither", LabelSelector[*projectv1.Project](labels.SelectorFromSet(labels.Set{"team": "a"})), withMeta(nil, nil), withMeta(nil, nil), false},
+		{"and all accept", And[*projectv1.Project](accept, accept), newProject("myproject"), newProject("myproject"), true},
+		{"and one rejects", And[*projectv1.Project](accept, reject), newProject("myproject"), newProject("myproject"), false},
+		{"and empty", And[*projectv1.Project](), newProject("myproject"), newProject("myproject"), true},
+		{"or one accepts", Or[*projectv1.Project](reject, accept), newProject("myproject"), newProject("myproject"), true},
+		{"or all reject", Or[*projectv1.Project](reject, reject), newProject("myproject"), newProject("myproject"), false},
+		{"or empty", Or[*projectv1.Project](), newProject("myproject"), newProject("myproject"), false},
+		{"not accept", Not[*projectv1.Project](accept), newProject("myproject"), newProject("myproject"), false},
+		{"not reject", Not[*projectv1.Project](reject), newProject("myproject"), newProject("myproject"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Update(tt.old, tt.new); got != tt.want {
+				t.Errorf("Update() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredicateAddsAndDeletes(t *testing.T) {
+	matching := withMeta(map[string]string{"team": "a"}, nil)
+	matching.Name = "team-a-dev"
+	other := newProject("other")
+
+	tests := []struct {
+		name string
+		p    Predicate[*projectv1.Project]
+		obj  *projectv1.Project
+		want bool
+	}{
+		// Change predicates only look at updates.
+		{"annotation changed", AnnotationChanged[*projectv1.Project](), other, true},
+		{"label changed", LabelChanged[*projectv1.Project](), other, true},
+		{"phase transition", PhaseTransition(), other, true},
+		{"name matches", NameMatches[*projectv1.Project](regexp.MustCompile(`^team-a-`)), matching, true},
+		{"name does not match", NameMatches[*projectv1.Project](regexp.MustCompile(`^team-a-`)), other, false},
+		{"selector matches", LabelSelector[*projectv1.Project](labels.SelectorFromSet(labels.Set{"team": "a"})), matching, true},
+		{"selector does not match", LabelSelector[*projectv1.Project](labels.SelectorFromSet(labels.Set{"team": "a"})), other, false},
+		{"and", And(NameMatches[*projectv1.Project](regexp.MustCompile(`^team-`)), LabelChanged[*projectv1.Project]()), matching, true},
+		{"or", Or(NameMatches[*projectv1.Project](regexp.MustCompile(`^x`)), NameMatches[*projectv1.Project](regexp.MustCompile(`^o`))), other, true},
+		{"not", Not(NameMatches[*projectv1.Project](regexp.MustCompile(`^team-`))), matching, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Add(tt.obj); got != tt.want {
+				t.Errorf("Add() = %v; want %v", got, tt.want)
+			}
+			if got := tt.p.Delete(tt.obj); got != tt.want {
+				t.Errorf("Delete() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package eventhandler
+
+import (
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ObjectAs returns obj as a T. For a cache.DeletedFinalStateUnknown
+// tombstone, which the informer delivers when it missed a delete and only
+// noticed on relist, it returns the last known state of the object. ok is
+// false when the object is not a T.
+func ObjectAs[T any](obj interface{}) (t T, ok bool) {
+	if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+		obj = tombstone.Obj
+	}
+	t, ok = obj.(T)
+	return t, ok
+}
+
+// ProjectFromObject is ObjectAs for projects.
+func ProjectFromObject(obj interface{}) (p *projectv1.Project, ok bool) {
+	return ObjectAs[*projectv1.Project](obj)
+}
@@ -0,0 +1,135 @@
+package eventhandler
+
+import (
+	"testing"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	fcache "k8s.io/client-go/tools/cache/testing"
+)
+
+func newProject(name string) *projectv1.Project {
+	return &projectv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+		Status:     projectv1.ProjectStatus{Phase: corev1.NamespaceActive},
+	}
+}
+
+func TestProjectFromObject(t *testing.T) {
+	p := newProject("myproject")
+
+	tests := []struct {
+		name string
+		obj  interface{}
+		want *projectv1.Project
+	}{
+		{"project", p, p},
+		{"tombstone", cache.DeletedFinalStateUnknown{Key: "myproject", Obj: p}, p},
+		{"tombstone of other type", cache.DeletedFinalStateUnknown{Key: "x", Obj: &corev1.Namespace{}}, nil},
+		{"other type", &corev1.Namespace{}, nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ProjectFromObject(tt.obj)
+			if ok != (tt.want != nil) || got != tt.want {
+				t.Errorf("ProjectFromObject() = %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectHandlerFuncsOnDeleteTombstone(t *testing.T) {
+	p := newProject("myproject")
+
+	var got *projectv1.Project
+	h := ProjectHandlerFuncs{DeleteFunc: func(p *projectv1.Project) { got = p }}
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: p.Name, Obj: p})
+
+	if got != p {
+		t.Fatalf("DeleteFunc got %v, want the project inside the tombstone", got)
+	}
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(cache.DeletedFinalStateUnknown{Key: p.Name, Obj: p})
+	if err != nil || key != p.Name {
+		t.Fatalf("DeletionHandlingMetaNamespaceKeyFunc() = %q, %v; want %q", key, err, p.Name)
+	}
+}
+
+// TestInformerTombstone makes a fake informer miss a delete, so that it
+// only notices on relist and delivers a cache.DeletedFinalStateUnknown.
+func TestInformerTombstone(t *testing.T) {
+	source := fcache.NewFakeControllerSource()
+	defer source.Shutdown()
+
+	informer := cache.NewSharedIndexInformer(source, &projectv1.Project{}, 0, cache.Indexers{})
+
+	tombstones := make(chan interface{}, 1)
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if _, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				tombstones <- obj
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := make(chan *projectv1.Project, 1)
+	if _, err := informer.AddEventHandler(ProjectHandlerFuncs{
+		DeleteFunc: func(p *projectv1.Project) { deleted <- p },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go informer.Run(stop)
+
+	source.Add(newProject("myproject"))
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		t.Fatal("cache not synced")
+	}
+	waitFor(t, func() bool {
+		_, exists, _ := informer.GetStore().GetByKey("myproject")
+		return exists
+	})
+
+	// Delete without a watch event, then break the watch so the informer
+	// relists and finds the project gone.
+	source.DeleteDropWatch(newProject("myproject"))
+	source.ResetWatch()
+
+	select {
+	case obj := <-tombstones:
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil || key != "myproject" {
+			t.Errorf("tombstone key = %q, %v; want myproject", key, err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("informer did not deliver a tombstone")
+	}
+
+	select {
+	case p := <-deleted:
+		if p.Name != "myproject" || p.Status.Phase != corev1.NamespaceActive {
+			t.Errorf("DeleteFunc got %s (%s), want the last known state of myproject", p.Name, p.Status.Phase)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("DeleteFunc was not called")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
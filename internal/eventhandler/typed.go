@@ -0,0 +1,114 @@
+package eventhandler
+
+import (
+	"log"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Predicate filters the events passed to a TypedEventHandler.
+type Predicate[T any] interface {
+	Add(obj T) bool
+	Update(oldObj, newObj T) bool
+	Delete(obj T) bool
+}
+
+// PredicateFuncs implements Predicate with functions. A nil function
+// accepts every event of its kind.
+type PredicateFuncs[T any] struct {
+	AddFunc    func(obj T) bool
+	UpdateFunc func(oldObj, newObj T) bool
+	DeleteFunc func(obj T) bool
+}
+
+func (p PredicateFuncs[T]) Add(obj T) bool {
+	return p.AddFunc == nil || p.AddFunc(obj)
+}
+
+func (p PredicateFuncs[T]) Update(oldObj, newObj T) bool {
+	return p.UpdateFunc == nil || p.UpdateFunc(oldObj, newObj)
+}
+
+func (p PredicateFuncs[T]) Delete(obj T) bool {
+	return p.DeleteFunc == nil || p.DeleteFunc(obj)
+}
+
+// TypedEventHandler is a cache.ResourceEventHandler that passes objects of
+// type T, such as *projectv1.Project or any other client-go type, to typed
+// callbacks. Tombstones are unwrapped, so DeleteFunc always gets the last
+// known object; other unexpected objects are logged and dropped. An event
+// is only passed on when every predicate accepts it. Any of the functions
+// may be nil.
+type TypedEventHandler[T runtime.Object] struct {
+	AddFunc    func(obj T, isInitialList bool)
+	UpdateFunc func(oldObj, newObj T)
+	DeleteFunc func(obj T)
+	Predicates []Predicate[T]
+}
+
+// ProjectHandlerFuncs is the TypedEventHandler for projects.
+type ProjectHandlerFuncs = TypedEventHandler[*projectv1.Project]
+
+var _ cache.ResourceEventHandler = ProjectHandlerFuncs{}
+
+func (h TypedEventHandler[T]) OnAdd(obj interface{}, isInInitialList bool) {
+	if h.AddFunc == nil {
+		return
+	}
+	t, ok := objectOrLog[T](obj)
+	if !ok {
+		return
+	}
+	for _, p := range h.Predicates {
+		if !p.Add(t) {
+			return
+		}
+	}
+	h.AddFunc(t, isInInitialList)
+}
+
+func (h TypedEventHandler[T]) OnUpdate(oldObj, newObj interface{}) {
+	if h.UpdateFunc == nil {
+		return
+	}
+	oldT, ok := objectOrLog[T](oldObj)
+	if !ok {
+		return
+	}
+	newT, ok := objectOrLog[T](newObj)
+	if !ok {
+		return
+	}
+	for _, p := range h.Predicates {
+		if !p.Update(oldT, newT) {
+			return
+		}
+	}
+	h.UpdateFunc(oldT, newT)
+}
+
+func (h TypedEventHandler[T]) OnDelete(obj interface{}) {
+	if h.DeleteFunc == nil {
+		return
+	}
+	t, ok := objectOrLog[T](obj)
+	if !ok {
+		return
+	}
+	for _, p := range h.Predicates {
+		if !p.Delete(t) {
+			return
+		}
+	}
+	h.DeleteFunc(t)
+}
+
+func objectOrLog[T any](obj interface{}) (T, bool) {
+	t, ok := ObjectAs[T](obj)
+	if !ok {
+		log.Printf("Unexpected object %T in event handler for %T", obj, t)
+	}
+	return t, ok
+}
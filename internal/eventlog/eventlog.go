@@ -0,0 +1,138 @@
+// Package eventlog turns project watch events into structured records and
+// writes them as JSON lines or logfmt, one record per line.
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Formats lists the values accepted by NewWriter, for flag help texts.
+const Formats = "json|logfmt"
+
+// Record is one project event.
+type Record struct {
+	Time            time.Time `json:"time"`
+	Type            string    `json:"type"`
+	Name            string    `json:"name"`
+	Phase           string    `json:"phase,omitempty"`
+	ResourceVersion string    `json:"resourceVersion,omitempty"`
+	// ChangedAnnotations are the annotation keys added, removed or changed
+	// since the previous event for the project. For the first event seen
+	// for a project, these are all of its annotation keys.
+	ChangedAnnotations []string `json:"changedAnnotations,omitempty"`
+}
+
+// Recorder builds records and remembers the annotations of every project
+// it has seen, so it can report which keys changed.
+type Recorder struct {
+	annotations map[string]map[string]string
+	now         func() time.Time
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		annotations: map[string]map[string]string{},
+		now:         time.Now,
+	}
+}
+
+// Record returns the record for an Added, Modified or Deleted event of p.
+func (r *Recorder) Record(eventType watch.EventType, p *projectv1.Project) Record {
+	rec := Record{
+		Time:            r.now().UTC(),
+		Type:            string(eventType),
+		Name:            p.Name,
+		Phase:           string(p.Status.Phase),
+		ResourceVersion: p.ResourceVersion,
+	}
+
+	if eventType == watch.Deleted {
+		delete(r.annotations, p.Name)
+		return rec
+	}
+	rec.ChangedAnnotations = changedKeys(r.annotations[p.Name], p.Annotations)
+	r.annotations[p.Name] = p.Annotations
+	return rec
+}
+
+func changedKeys(before, after map[string]string) []string {
+	var keys []string
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Writer writes records, one per line.
+type Writer interface {
+	Write(rec Record) error
+}
+
+// NewWriter returns a writer for format writing to out.
+func NewWriter(out io.Writer, format string) (Writer, error) {
+	switch format {
+	case "", "json":
+		return &jsonWriter{enc: json.NewEncoder(out)}, nil
+	case "logfmt":
+		return &logfmtWriter{out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown event format %q, expected one of %s", format, Formats)
+	}
+}
+
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonWriter) Write(rec Record) error {
+	return w.enc.Encode(rec)
+}
+
+type logfmtWriter struct {
+	out io.Writer
+}
+
+func (w *logfmtWriter) Write(rec Record) error {
+	fields := []string{
+		"time=" + rec.Time.Format(time.RFC3339Nano),
+		"type=" + logfmtValue(rec.Type),
+		"name=" + logfmtValue(rec.Name),
+	}
+	if rec.Phase != "" {
+		fields = append(fields, "phase="+logfmtValue(rec.Phase))
+	}
+	if rec.ResourceVersion != "" {
+		fields = append(fields, "resourceVersion="+logfmtValue(rec.ResourceVersion))
+	}
+	if len(rec.ChangedAnnotations) > 0 {
+		fields = append(fields, "changedAnnotations="+logfmtValue(strings.Join(rec.ChangedAnnotations, ",")))
+	}
+
+	_, err := fmt.Fprintln(w.out, strings.Join(fields, " "))
+	return err
+}
+
+// logfmtValue quotes v when it is empty or contains spaces, quotes or '='.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\"=\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
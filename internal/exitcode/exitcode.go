@@ -0,0 +1,33 @@
+// Package exitcode maps errors returned by project operations to process
+// exit codes.
+package exitcode
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	OK            = 0
+	Error         = 1
+	AlreadyExists = 2
+	Forbidden     = 3
+	Timeout       = 4
+)
+
+func For(err error) int {
+	switch {
+	case err == nil:
+		return OK
+	case apierrors.IsAlreadyExists(err):
+		return AlreadyExists
+	case apierrors.IsForbidden(err):
+		return Forbidden
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return Timeout
+	default:
+		return Error
+	}
+}
@@ -0,0 +1,224 @@
+// Package lifecycle coordinates the shutdown of a program's components:
+// informer factories, workqueues, workers and HTTP servers.
+//
+// The first SIGINT or SIGTERM cancels the manager's context. Wait then
+// stops the registered components in reverse registration order, waits
+// for the goroutines started with Go, and gives up after the grace
+// period. A second signal exits the process immediately.
+//
+// Programs typically defer Shutdown and Wait right after New, so that
+// loops watching the context end on the first signal and the deferred
+// shutdown runs. Code after New should return errors rather than call
+// log.Fatal, because os.Exit skips deferred calls.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod is used when New is given a zero grace period.
+const DefaultGracePeriod = 30 * time.Second
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager tracks the components of a program and shuts them down in order.
+type Manager struct {
+	grace  time.Duration
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	hooks   []hook
+	running map[string]int
+	wg      sync.WaitGroup
+}
+
+// New returns a manager whose context is canceled by the first SIGINT or
+// SIGTERM. A second signal exits with status 1.
+func New(gracePeriod time.Duration) *Manager {
+	m := newManager(context.Background(), gracePeriod)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("Received %s, shutting down (send again to force exit)", sig)
+		m.cancel()
+		<-signals
+		log.Println("Received second signal, exiting")
+		os.Exit(1)
+	}()
+	return m
+}
+
+// NewWithContext returns a manager whose context is derived from ctx, for
+// programs that handle signals themselves.
+func NewWithContext(ctx context.Context, gracePeriod time.Duration) *Manager {
+	return newManager(ctx, gracePeriod)
+}
+
+func newManager(ctx context.Context, gracePeriod time.Duration) *Manager {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Manager{
+		grace:   gracePeriod,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: map[string]int{},
+	}
+}
+
+// Context is canceled when shutdown begins. Components should stop taking
+// new work when it is done.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Shutdown begins shutdown, as if a signal had been received.
+func (m *Manager) Shutdown() {
+	m.cancel()
+}
+
+// OnShutdown registers stop to be called during shutdown. Hooks run one at
+// a time in reverse registration order, after the context is canceled. The
+// context passed to stop expires at the end of the grace period.
+func (m *Manager) OnShutdown(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Go runs fn in a new goroutine. Shutdown waits for fn to return, after the
+// hooks have run.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.mu.Lock()
+	m.running[name]++
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			m.mu.Lock()
+			m.running[name]--
+			if m.running[name] == 0 {
+				delete(m.running, name)
+			}
+			m.mu.Unlock()
+		}()
+		fn(m.ctx)
+	}()
+}
+
+// InformerFactory is implemented by the shared informer factories.
+type InformerFactory interface {
+	Start(stopCh <-chan struct{})
+	Shutdown()
+}
+
+// AddInformerFactory starts factory and registers its shutdown, which waits
+// for the informer goroutines to exit.
+func (m *Manager) AddInformerFactory(name string, factory InformerFactory) {
+	factory.Start(m.ctx.Done())
+	m.OnShutdown(name, func(ctx context.Context) error {
+		return waitFor(ctx, factory.Shutdown)
+	})
+}
+
+// Queue is implemented by the client-go workqueues.
+type Queue interface {
+	ShutDown()
+	ShutDownWithDrain()
+}
+
+// AddQueue registers the shutdown of queue. Shutdown waits for the items
+// being processed to be marked done; if the grace period ends first, the
+// queue is shut down without draining.
+func (m *Manager) AddQueue(name string, queue Queue) {
+	m.OnShutdown(name, func(ctx context.Context) error {
+		err := waitFor(ctx, queue.ShutDownWithDrain)
+		if err != nil {
+			queue.ShutDown()
+		}
+		return err
+	})
+}
+
+// AddHTTPServer serves srv in a new goroutine and registers its graceful
+// shutdown.
+func (m *Manager) AddHTTPServer(name string, srv *http.Server) {
+	m.Go(name, func(context.Context) {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("%s: %v", name, err)
+			m.cancel()
+		}
+	})
+	m.OnShutdown(name, srv.Shutdown)
+}
+
+// Wait blocks until the context is canceled, then shuts everything down.
+// It returns an error naming the components that did not stop within the
+// grace period.
+func (m *Manager) Wait() error {
+	<-m.ctx.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.grace)
+	defer cancel()
+
+	m.mu.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		log.Printf("Stopping %s", h.name)
+		if err := h.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+
+	if err := waitFor(ctx, m.wg.Wait); err != nil {
+		m.mu.Lock()
+		for name, n := range m.running {
+			errs = append(errs, fmt.Errorf("%s: %d goroutine(s) still running: %w", name, n, err))
+		}
+		m.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	log.Println("Shutdown complete")
+	return nil
+}
+
+// waitFor runs fn in a goroutine and waits until it returns or ctx is done.
+func waitFor(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestWaitStopsInReverseOrder(t *testing.T) {
+	m := NewWithContext(context.Background(), time.Second)
+	var mu sync.Mutex
+	var got []string
+	for _, name := range []string{"informers", "queue", "server"} {
+		m.OnShutdown(name, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, name)
+			return nil
+		})
+	}
+
+	m.Shutdown()
+	if err := m.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if want := []string{"server", "queue", "informers"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stopped %v; want %v", got, want)
+	}
+}
+
+func TestAddQueue(t *testing.T) {
+	tests := []struct {
+		name string
+		// work is how long the in-flight item takes after shutdown begins.
+		work    time.Duration
+		grace   time.Duration
+		wantErr error
+	}{
+		{"drains in-flight item", 50 * time.Millisecond, 5 * time.Second, nil},
+		{"gives up after grace period", 5 * time.Second, 50 * time.Millisecond, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewWithContext(context.Background(), tt.grace)
+			queue := workqueue.NewTyped[string]()
+			m.AddQueue("queue", queue)
+
+			queue.Add("a")
+			queue.Add("b")
+			item, _ := queue.Get()
+			finished := make(chan struct{})
+			go func() {
+				defer close(finished)
+				select {
+				case <-time.After(tt.work):
+				case <-t.Context().Done():
+				}
+				queue.Done(item)
+			}()
+
+			m.Shutdown()
+			err := m.Wait()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Wait() = %v; want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				select {
+				case <-finished:
+				default:
+					t.Error("Wait() returned before the in-flight item was done")
+				}
+			}
+			if !queue.ShuttingDown() {
+				t.Error("queue was not shut down")
+			}
+		})
+	}
+}
+
+func TestAddHTTPServer(t *testing.T) {
+	m := NewWithContext(context.Background(), 5*time.Second)
+	srv := &http.Server{Addr: "127.0.0.1:0"}
+	m.AddHTTPServer("server", srv)
+
+	m.Shutdown()
+	if err := m.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("ListenAndServe() after Wait = %v; want %v", err, http.ErrServerClosed)
+	}
+}
+
+func TestAddHTTPServerListenError(t *testing.T) {
+	m := NewWithContext(context.Background(), 5*time.Second)
+	m.AddHTTPServer("server", &http.Server{Addr: "127.0.0.1:-1"})
+
+	// A server that cannot listen begins shutdown of the whole program.
+	select {
+	case <-m.Context().Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("context not canceled after listen error")
+	}
+	if err := m.Wait(); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+}
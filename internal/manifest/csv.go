@@ -0,0 +1,177 @@
+// Package manifest reads the desired projects from manifest files.
+//
+// A CSV manifest starts with a header line naming its columns. Name,
+// DisplayName and Description are required; Labels, Annotations, Requester
+// and NodeSelector are optional. Labels and Annotations hold comma separated
+// key=value pairs, so the field has to be quoted:
+//
+//	Name,DisplayName,Description,Labels
+//	myproj01,project No.01,my first project,"team=a,env=dev"
+package manifest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+)
+
+const (
+	columnName         = "name"
+	columnDisplayName  = "displayname"
+	columnDescription  = "description"
+	columnLabels       = "labels"
+	columnAnnotations  = "annotations"
+	columnRequester    = "requester"
+	columnNodeSelector = "nodeselector"
+)
+
+var requiredColumns = []string{columnName, columnDisplayName, columnDescription}
+
+var knownColumns = map[string]bool{
+	columnName:         true,
+	columnDisplayName:  true,
+	columnDescription:  true,
+	columnLabels:       true,
+	columnAnnotations:  true,
+	columnRequester:    true,
+	columnNodeSelector: true,
+}
+
+// ReadCSV reads and validates a CSV manifest.
+func ReadCSV(csvFile string) ([]project.Spec, error) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	specs, err := ParseCSV(file)
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		verr.File = csvFile
+	}
+	return specs, err
+}
+
+// ParseCSV parses a CSV manifest. Problems in individual rows do not stop
+// parsing; they are all returned together as a *ValidationError.
+func ParseCSV(in io.Reader) ([]project.Spec, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns, verr := parseHeader(header)
+	if verr != nil {
+		return nil, verr
+	}
+
+	checker := newSpecChecker()
+	var specs []project.Spec
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		location := fmt.Sprintf("line %d", line)
+
+		if len(row) != len(columns) {
+			checker.verr.add(location, "expected %d columns, got %d", len(columns), len(row))
+			continue
+		}
+
+		spec, problems := parseRow(columns, row)
+		for _, p := range problems {
+			checker.verr.add(location, "%s", p)
+		}
+		checker.check(location, spec)
+
+		specs = append(specs, spec)
+	}
+
+	if err := checker.err(); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name)
+}
+
+func parseHeader(header []string) ([]string, *ValidationError) {
+	verr := &ValidationError{}
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+
+	for i, h := range header {
+		c := normalizeColumn(h)
+		switch {
+		case !knownColumns[c]:
+			verr.add("line 1", "unknown column %q", h)
+		case present[c]:
+			verr.add("line 1", "duplicate column %q", h)
+		}
+		present[c] = true
+		columns[i] = c
+	}
+	for _, c := range requiredColumns {
+		if !present[c] {
+			verr.add("line 1", "missing required column %q", c)
+		}
+	}
+
+	if len(verr.Problems) > 0 {
+		return nil, verr
+	}
+	return columns, nil
+}
+
+func parseRow(columns []string, row []string) (project.Spec, []string) {
+	var (
+		spec     project.Spec
+		problems []string
+		err      error
+	)
+
+	for i, c := range columns {
+		v := strings.TrimSpace(row[i])
+		switch c {
+		case columnName:
+			spec.Name = v
+		case columnDisplayName:
+			spec.DisplayName = v
+		case columnDescription:
+			spec.Description = v
+		case columnLabels:
+			if spec.Labels, err = project.ParseKeyValues(v); err != nil {
+				problems = append(problems, fmt.Sprintf("labels: %v", err))
+			}
+		case columnAnnotations:
+			if spec.Annotations, err = project.ParseKeyValues(v); err != nil {
+				problems = append(problems, fmt.Sprintf("annotations: %v", err))
+			}
+		case columnRequester:
+			spec.Requester = v
+		case columnNodeSelector:
+			spec.NodeSelector = v
+		}
+	}
+	return spec, problems
+}
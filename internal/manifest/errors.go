@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+)
+
+// Problem is a single validation failure in a manifest. Location is e.g.
+// "line 3" for CSV or "document 2" for YAML.
+type Problem struct {
+	Location string
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Location == "" {
+		return p.Message
+	}
+	return p.Location + ": " + p.Message
+}
+
+// ValidationError collects every problem found in a manifest so they can be
+// reported at once.
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) add(location string, format string, args ...interface{}) {
+	e.Problems = append(e.Problems, Problem{Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	if e.File != "" {
+		lines = append(lines, fmt.Sprintf("%s: %d problem(s)", e.File, len(e.Problems)))
+	} else {
+		lines = append(lines, fmt.Sprintf("%d problem(s)", len(e.Problems)))
+	}
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// specChecker validates specs and detects projects declared twice.
+type specChecker struct {
+	verr *ValidationError
+	seen map[string]string
+}
+
+func newSpecChecker() *specChecker {
+	return &specChecker{verr: &ValidationError{}, seen: map[string]string{}}
+}
+
+func (c *specChecker) check(location string, spec project.Spec) {
+	for _, p := range spec.Validate() {
+		c.verr.add(location, "%s", p)
+	}
+	if spec.Name == "" {
+		return
+	}
+	if first, ok := c.seen[spec.Name]; ok {
+		c.verr.add(location, "project %q already declared at %s", spec.Name, first)
+		return
+	}
+	c.seen[spec.Name] = location
+}
+
+func (c *specChecker) err() error {
+	if len(c.verr.Problems) > 0 {
+		return c.verr
+	}
+	return nil
+}
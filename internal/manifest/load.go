@@ -0,0 +1,22 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+)
+
+// Load reads a manifest, choosing the format from the file extension:
+// .csv, or .yaml, .yml and .json.
+func Load(path string) ([]project.Spec, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return ReadCSV(path)
+	case ".yaml", ".yml", ".json":
+		return ReadYAML(path)
+	default:
+		return nil, fmt.Errorf("%s: unsupported manifest extension %q, expected .csv, .yaml, .yml or .json", path, ext)
+	}
+}
@@ -0,0 +1,173 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectv1 "github.com/openshift/api/project/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Entry is one project in a YAML or JSON list manifest:
+//
+//	# projects.yaml
+//	- name: myproj01
+//	  displayName: project No.01
+//	  description: my first project
+//	  labels:
+//	    team: a
+type Entry struct {
+	Name         string            `json:"name"`
+	DisplayName  string            `json:"displayName,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Requester    string            `json:"requester,omitempty"`
+	NodeSelector string            `json:"nodeSelector,omitempty"`
+}
+
+func (e Entry) Spec() project.Spec {
+	return project.Spec{
+		Name:         e.Name,
+		DisplayName:  e.DisplayName,
+		Description:  e.Description,
+		Labels:       e.Labels,
+		Annotations:  e.Annotations,
+		Requester:    e.Requester,
+		NodeSelector: e.NodeSelector,
+	}
+}
+
+func specFromProjectRequest(pr *projectv1.ProjectRequest) project.Spec {
+	return project.Spec{
+		Name:        pr.Name,
+		DisplayName: pr.DisplayName,
+		Description: pr.Description,
+		Labels:      pr.Labels,
+		Annotations: pr.Annotations,
+	}
+}
+
+// ReadYAML reads and validates a YAML or JSON manifest.
+func ReadYAML(yamlFile string) ([]project.Spec, error) {
+	file, err := os.Open(yamlFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	specs, err := ParseYAML(file)
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		verr.File = yamlFile
+	}
+	return specs, err
+}
+
+// ParseYAML parses a YAML or JSON manifest. Every document is either a list
+// of entries, a ProjectRequest or a List of ProjectRequests.
+func ParseYAML(in io.Reader) ([]project.Spec, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	checker := newSpecChecker()
+	var specs []project.Spec
+
+	for doc := 1; ; doc++ {
+		data, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		location := fmt.Sprintf("document %d", doc)
+		found, err := parseDocument(data)
+		if err != nil {
+			checker.verr.add(location, "%v", err)
+			continue
+		}
+		for i, spec := range found {
+			loc := location
+			if len(found) > 1 {
+				loc = fmt.Sprintf("%s, entry %d", location, i+1)
+			}
+			checker.check(loc, spec)
+		}
+		specs = append(specs, found...)
+	}
+
+	if err := checker.err(); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+func parseDocument(data []byte) ([]project.Spec, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var entries []Entry
+		if err := yaml.UnmarshalStrict(data, &entries); err != nil {
+			return nil, err
+		}
+		specs := make([]project.Spec, 0, len(entries))
+		for _, e := range entries {
+			specs = append(specs, e.Spec())
+		}
+		return specs, nil
+	}
+
+	var typeMeta struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+
+	switch typeMeta.Kind {
+	case "ProjectRequest":
+		spec, err := parseProjectRequest(data)
+		if err != nil {
+			return nil, err
+		}
+		return []project.Spec{spec}, nil
+	case "List", "ProjectRequestList":
+		specs := make([]project.Spec, 0, len(typeMeta.Items))
+		for i, item := range typeMeta.Items {
+			spec, err := parseProjectRequest(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			specs = append(specs, spec)
+		}
+		return specs, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, expected ProjectRequest, List or a list of entries", typeMeta.Kind)
+	}
+}
+
+func parseProjectRequest(data []byte) (project.Spec, error) {
+	var pr projectv1.ProjectRequest
+	if err := yaml.UnmarshalStrict(data, &pr); err != nil {
+		return project.Spec{}, err
+	}
+	if pr.Kind != "ProjectRequest" {
+		return project.Spec{}, fmt.Errorf("unsupported kind %q, expected ProjectRequest", pr.Kind)
+	}
+	return specFromProjectRequest(&pr), nil
+}
@@ -0,0 +1,121 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// listPrinter collects projects and renders them as one ProjectList, or as
+// the project itself when single is set.
+type listPrinter struct {
+	out     io.Writer
+	render  func(io.Writer, interface{}) error
+	single  bool
+	items   []projectv1.Project
+	flushed bool
+}
+
+func (l *listPrinter) PrintProject(p *projectv1.Project) error {
+	item := *p
+	item.APIVersion = projectv1.GroupVersion.String()
+	item.Kind = "Project"
+	l.items = append(l.items, item)
+	return nil
+}
+
+func (l *listPrinter) Flush() error {
+	if l.flushed {
+		return nil
+	}
+	l.flushed = true
+
+	var obj interface{}
+	if l.single && len(l.items) == 1 {
+		obj = &l.items[0]
+	} else {
+		list := &projectv1.ProjectList{Items: l.items}
+		list.APIVersion = projectv1.GroupVersion.String()
+		list.Kind = "ProjectList"
+		obj = list
+	}
+	return l.render(l.out, obj)
+}
+
+// Streaming is false: the whole list is rendered as one document, so it
+// must only be flushed once every page has been added.
+func (l *listPrinter) Streaming() bool {
+	return false
+}
+
+func encoderFor(format string) func(io.Writer, interface{}) error {
+	if format == "yaml" {
+		return func(out io.Writer, obj interface{}) error {
+			data, err := yaml.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(data)
+			return err
+		}
+	}
+
+	return func(out io.Writer, obj interface{}) error {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "    ")
+		return enc.Encode(obj)
+	}
+}
+
+func jsonPathRenderer(tmpl string) (func(io.Writer, interface{}) error, error) {
+	jp := jsonpath.New("out").AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("parsing jsonpath %q: %w", tmpl, err)
+	}
+
+	return func(out io.Writer, obj interface{}) error {
+		data, err := toUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		return jp.Execute(out, data)
+	}, nil
+}
+
+func goTemplateRenderer(tmpl string) (func(io.Writer, interface{}) error, error) {
+	t, err := template.New("out").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go-template %q: %w", tmpl, err)
+	}
+
+	return func(out io.Writer, obj interface{}) error {
+		data, err := toUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		return t.Execute(out, data)
+	}, nil
+}
+
+// toUnstructured converts obj to maps keyed by the JSON field names, which
+// is what jsonpath and go-template expressions refer to.
+func toUnstructured(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var u interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,118 @@
+// Package printer formats projects for terminal output.
+//
+// Supported formats are table (the default), wide, name, csv, json, yaml,
+// jsonpath=TEMPLATE and go-template=TEMPLATE. The line oriented formats
+// write each project as soon as Flush is called, so listings can be
+// streamed page by page; json, yaml and the template formats collect the
+// projects and render them as a single List on the final Flush.
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectv1 "github.com/openshift/api/project/v1"
+)
+
+// Formats lists the values accepted by New, for flag help texts.
+const Formats = "table|wide|name|csv|json|yaml|jsonpath=...|go-template=..."
+
+// Printer writes projects in one output format.
+type Printer interface {
+	// PrintProject adds p to the output.
+	PrintProject(p *projectv1.Project) error
+	// Flush writes everything added so far. Line oriented printers can be
+	// flushed repeatedly; list printers write their output on the first
+	// Flush and ignore later projects.
+	Flush() error
+	// Streaming reports whether the printer is line oriented, so it can be
+	// flushed after every page of a paginated list.
+	Streaming() bool
+}
+
+// New returns a printer for format writing to out.
+func New(out io.Writer, format string) (Printer, error) {
+	name, arg, _ := strings.Cut(format, "=")
+
+	switch name {
+	case "", "table":
+		return newTablePrinter(out, false), nil
+	case "wide":
+		return newTablePrinter(out, true), nil
+	case "name":
+		return &namePrinter{out: out}, nil
+	case "csv":
+		return newCSVPrinter(out), nil
+	case "json", "yaml":
+		return &listPrinter{out: out, render: encoderFor(name)}, nil
+	case "jsonpath":
+		render, err := jsonPathRenderer(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &listPrinter{out: out, render: render}, nil
+	case "go-template":
+		render, err := goTemplateRenderer(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &listPrinter{out: out, render: render}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected one of %s", format, Formats)
+	}
+}
+
+// PrintProjects prints projects in format in one go.
+func PrintProjects(out io.Writer, format string, projects []projectv1.Project) error {
+	p, err := New(out, format)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		if err := p.PrintProject(&projects[i]); err != nil {
+			return err
+		}
+	}
+	return p.Flush()
+}
+
+// PrintProject prints a single project in format. The json, yaml and
+// template formats render the project itself rather than a List.
+func PrintProject(out io.Writer, format string, p *projectv1.Project) error {
+	pr, err := New(out, format)
+	if err != nil {
+		return err
+	}
+	if lp, ok := pr.(*listPrinter); ok {
+		lp.single = true
+	}
+	if err := pr.PrintProject(p); err != nil {
+		return err
+	}
+	return pr.Flush()
+}
+
+// Summary is a one line key=value description of p for log messages.
+func Summary(p *projectv1.Project) string {
+	return fmt.Sprintf("name=%s, displayName=%s, description=%s, status=%s",
+		p.Name, project.DisplayName(p), project.Description(p), p.Status.Phase)
+}
+
+type namePrinter struct {
+	out io.Writer
+}
+
+func (n *namePrinter) PrintProject(p *projectv1.Project) error {
+	_, err := fmt.Fprintf(n.out, "project.project.openshift.io/%s\n", p.Name)
+	return err
+}
+
+func (n *namePrinter) Flush() error {
+	return nil
+}
+
+func (n *namePrinter) Streaming() bool {
+	return true
+}
@@ -0,0 +1,91 @@
+package printer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	projectv1 "github.com/openshift/api/project/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+type tablePrinter struct {
+	w           *tabwriter.Writer
+	wide        bool
+	wroteHeader bool
+	now         func() time.Time
+}
+
+func newTablePrinter(out io.Writer, wide bool) *tablePrinter {
+	return &tablePrinter{
+		w:    tabwriter.NewWriter(out, 0, 8, 2, ' ', 0),
+		wide: wide,
+		now:  time.Now,
+	}
+}
+
+func (t *tablePrinter) PrintProject(p *projectv1.Project) error {
+	if !t.wroteHeader {
+		if t.wide {
+			fmt.Fprintln(t.w, "NAME\tDISPLAY NAME\tDESCRIPTION\tSTATUS\tREQUESTER\tCREATED\tAGE")
+		} else {
+			fmt.Fprintln(t.w, "NAME\tDISPLAY NAME\tDESCRIPTION")
+		}
+		t.wroteHeader = true
+	}
+
+	if !t.wide {
+		_, err := fmt.Fprintf(t.w, "%s\t%s\t%s\n", p.Name, project.DisplayName(p), project.Description(p))
+		return err
+	}
+
+	created, age := "<unknown>", "<unknown>"
+	if !p.CreationTimestamp.IsZero() {
+		created = p.CreationTimestamp.UTC().Format(time.RFC3339)
+		age = duration.HumanDuration(t.now().Sub(p.CreationTimestamp.Time))
+	}
+	_, err := fmt.Fprintf(t.w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		p.Name, project.DisplayName(p), project.Description(p), p.Status.Phase, project.Requester(p), created, age)
+	return err
+}
+
+func (t *tablePrinter) Flush() error {
+	return t.w.Flush()
+}
+
+func (t *tablePrinter) Streaming() bool {
+	return true
+}
+
+// csvPrinter writes the columns of projects.csv, so its output can be fed
+// back to bulk-create.
+type csvPrinter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVPrinter(out io.Writer) *csvPrinter {
+	return &csvPrinter{w: csv.NewWriter(out)}
+}
+
+func (c *csvPrinter) PrintProject(p *projectv1.Project) error {
+	if !c.wroteHeader {
+		if err := c.w.Write([]string{"Name", "DisplayName", "Description"}); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	return c.w.Write([]string{p.Name, project.DisplayName(p), project.Description(p)})
+}
+
+func (c *csvPrinter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvPrinter) Streaming() bool {
+	return true
+}
@@ -0,0 +1,204 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Outcome is what BulkCreate did with one spec.
+type Outcome string
+
+const (
+	Created   Outcome = "created"
+	Unchanged Outcome = "unchanged"
+	Drifted   Outcome = "drifted"
+	Failed    Outcome = "failed"
+	// Skipped specs were never sent because the run was cancelled.
+	Skipped Outcome = "skipped"
+)
+
+// Result is the outcome of one spec passed to BulkCreate.
+type Result struct {
+	Spec    Spec
+	Outcome Outcome
+	Project *projectv1.Project
+	// Drift lists the display name, description, labels and annotations of
+	// an existing project that differ from Spec.
+	Drift []string
+	Err   error
+}
+
+func (r Result) String() string {
+	switch r.Outcome {
+	case Drifted:
+		return fmt.Sprintf("%s %s: %s", r.Spec.Name, r.Outcome, strings.Join(r.Drift, ", "))
+	case Failed, Skipped:
+		return fmt.Sprintf("%s %s: %v", r.Spec.Name, r.Outcome, r.Err)
+	default:
+		return fmt.Sprintf("%s %s", r.Spec.Name, r.Outcome)
+	}
+}
+
+// Summary collects the results of BulkCreate in input order.
+type Summary struct {
+	Results []Result
+}
+
+func (s *Summary) Count(o Outcome) int {
+	n := 0
+	for _, r := range s.Results {
+		if r.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Summary) String() string {
+	return fmt.Sprintf("created=%d unchanged=%d drifted=%d failed=%d skipped=%d",
+		s.Count(Created), s.Count(Unchanged), s.Count(Drifted), s.Count(Failed), s.Count(Skipped))
+}
+
+// Err returns an error when at least one spec failed or was skipped.
+func (s *Summary) Err() error {
+	failed, skipped := s.Count(Failed), s.Count(Skipped)
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d of %d projects failed, %d skipped", failed, len(s.Results), skipped)
+	case skipped > 0:
+		return fmt.Errorf("%d of %d projects skipped", skipped, len(s.Results))
+	}
+	return nil
+}
+
+var errStopped = errors.New("stopped after an earlier failure")
+
+// BulkOptions controls BulkCreate.
+type BulkOptions struct {
+	// Idempotent treats existing projects as success, compares them with the
+	// spec and keeps going after failures. Without it BulkCreate stops
+	// sending new requests after the first error.
+	Idempotent bool
+	// Concurrency is the number of requests in flight. Values below 1 mean 1.
+	Concurrency int
+	// Timeout bounds each create. Zero means no per-request timeout.
+	Timeout time.Duration
+	// OnResult, if set, is called as soon as each spec has been handled.
+	// Calls are serialized.
+	OnResult func(Result)
+}
+
+// BulkCreate creates the projects described by specs with a pool of
+// opts.Concurrency workers and returns their results in input order. Once
+// ctx is done no new requests are sent; requests already in flight run to
+// completion (bounded by opts.Timeout) and the rest are reported as Skipped.
+func BulkCreate(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, specs []Spec, opts BulkOptions) *Summary {
+	workers := max(opts.Concurrency, 1)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]Result, len(specs))
+	indexes := make(chan int)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r := createOne(ctx, client, kube, specs[i], opts)
+
+				mu.Lock()
+				results[i] = r
+				if opts.OnResult != nil {
+					opts.OnResult(r)
+				}
+				mu.Unlock()
+
+				if r.Outcome == Failed && !opts.Idempotent {
+					cancel(errStopped)
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range specs {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	cause := context.Cause(ctx)
+	for i := range results {
+		if results[i].Outcome == "" {
+			results[i] = Result{Spec: specs[i], Outcome: Skipped, Err: cause}
+		}
+	}
+	return &Summary{Results: results}
+}
+
+func createOne(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, spec Spec, opts BulkOptions) Result {
+	// Let a request that was already dispatched finish even if ctx is
+	// cancelled meanwhile, so projects are not left half configured.
+	ctx = context.WithoutCancel(ctx)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	p, err := Create(ctx, client, kube, spec, CreateOptions{})
+	if err == nil {
+		return Result{Spec: spec, Outcome: Created, Project: p}
+	}
+	if !opts.Idempotent || !apierrors.IsAlreadyExists(err) {
+		return Result{Spec: spec, Outcome: Failed, Err: err}
+	}
+
+	p, err = client.ProjectV1().Projects().Get(ctx, spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return Result{Spec: spec, Outcome: Failed, Err: err}
+	}
+
+	// Compare the labels and annotations too: they are applied after the
+	// ProjectRequest, so a failed earlier run may have left them unset.
+	if drift := syncChanges(spec, p); len(drift) > 0 {
+		return Result{Spec: spec, Outcome: Drifted, Project: p, Drift: drift}
+	}
+	return Result{Spec: spec, Outcome: Unchanged, Project: p}
+}
+
+// Drift describes how the display name and description of p differ from
+// spec.
+func Drift(spec Spec, p *projectv1.Project) []string {
+	var drift []string
+	if got := DisplayName(p); got != spec.DisplayName {
+		drift = append(drift, fmt.Sprintf("display name is %q, want %q", got, spec.DisplayName))
+	}
+	if got := Description(p); got != spec.Description {
+		drift = append(drift, fmt.Sprintf("description is %q, want %q", got, spec.Description))
+	}
+	return drift
+}
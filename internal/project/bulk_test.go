@@ -0,0 +1,56 @@
+package project
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectfake "github.com/openshift/client-go/project/clientset/versioned/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestBulkCreateExistingProjectDrift(t *testing.T) {
+	existing := &projectv1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myproj01",
+			Annotations: map[string]string{
+				AnnotationDisplayName: "project No.01",
+				AnnotationDescription: "my first project",
+			},
+		},
+	}
+	spec := Spec{Name: "myproj01", DisplayName: "project No.01", Description: "my first project"}
+
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		outcome Outcome
+		drift   []string
+	}{
+		{"same metadata", nil, Unchanged, nil},
+		// The namespace update of an earlier run failed after the
+		// ProjectRequest was created, so the label was never applied.
+		{"missing label", map[string]string{"team": "a"}, Drifted, []string{`label team is "", want "a"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := projectfake.NewClientset(existing)
+			client.PrependReactor("create", "projectrequests", func(clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewAlreadyExists(projectv1.Resource("projects"), "myproj01")
+			})
+
+			s := spec
+			s.Labels = tt.labels
+			summary := BulkCreate(context.Background(), client, nil, []Spec{s}, BulkOptions{Idempotent: true})
+
+			r := summary.Results[0]
+			if r.Outcome != tt.outcome || !reflect.DeepEqual(r.Drift, tt.drift) {
+				t.Errorf("BulkCreate() = %s %v; want %s %v", r.Outcome, r.Drift, tt.outcome, tt.drift)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// CreateOptions controls Create.
+type CreateOptions struct {
+	// WaitActive blocks until the project reaches the Active phase.
+	WaitActive bool
+	// Timeout bounds the wait. Zero means wait until ctx is done.
+	Timeout time.Duration
+}
+
+// Create requests the project described by spec, applies its labels and
+// annotations and optionally waits for it to become Active. The Project API
+// refuses label and annotation changes, so they are written to the
+// namespace through kube, which may be nil when spec has none.
+func Create(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, spec Spec, opts CreateOptions) (*projectv1.Project, error) {
+	p, err := client.ProjectV1().ProjectRequests().Create(ctx, spec.ProjectRequest(), metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.Labels) > 0 || len(spec.NamespaceAnnotations()) > 0 {
+		if err := applyMetadata(ctx, kube, spec, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("setting labels and annotations on %s: %w", spec.Name, err)
+		}
+		p, err = client.ProjectV1().Projects().Get(ctx, spec.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.WaitActive {
+		if err := WaitForActive(ctx, client, []string{spec.Name}, WaitOptions{Timeout: opts.Timeout}); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+func applyMetadata(ctx context.Context, kube kubernetes.Interface, spec Spec, opts metav1.UpdateOptions) error {
+	if kube == nil {
+		return fmt.Errorf("no Kubernetes client to update namespace %s", spec.Name)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns, err := kube.CoreV1().Namespaces().Get(ctx, spec.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		ns = ns.DeepCopy()
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		for k, v := range spec.Labels {
+			ns.Labels[k] = v
+		}
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		for k, v := range spec.NamespaceAnnotations() {
+			ns.Annotations[k] = v
+		}
+
+		_, err = kube.CoreV1().Namespaces().Update(ctx, ns, opts)
+		return err
+	})
+}
@@ -0,0 +1,255 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// DefaultStuckAfter is how long a project may stay Terminating before it
+// is reported as stuck, when DeleteOptions.StuckAfter is not set.
+const DefaultStuckAfter = 30 * time.Second
+
+// deleteCheckInterval is how often pending projects are fetched again while
+// waiting, to catch deletions a watch may have missed and to report
+// projects that are stuck.
+const deleteCheckInterval = 5 * time.Second
+
+// DeleteOptions controls Delete and WaitForDeleted.
+type DeleteOptions struct {
+	// Wait makes Delete block until the projects are gone.
+	Wait bool
+	// Timeout bounds the wait. Zero means wait until ctx is done.
+	Timeout time.Duration
+	// StuckAfter is how long a project may be Terminating before OnStuck
+	// is called for it.
+	StuckAfter time.Duration
+	// OnDeleted, if set, is called once for every project that is gone.
+	OnDeleted func(name string)
+	// OnStuck, if set, is called once for every project that is still
+	// Terminating after StuckAfter.
+	OnStuck func(t Terminating)
+}
+
+// Terminating describes a project that has not been removed yet, with the
+// finalizers and conditions that are holding it.
+type Terminating struct {
+	Name       string
+	Phase      corev1.NamespacePhase
+	Since      *metav1.Time
+	Finalizers []string
+	// Conditions are the namespace conditions that are true, such as
+	// NamespaceContentRemaining or NamespaceFinalizersRemaining.
+	Conditions []corev1.NamespaceCondition
+}
+
+func terminatingState(p *projectv1.Project) Terminating {
+	t := Terminating{
+		Name:  p.Name,
+		Phase: p.Status.Phase,
+		Since: p.DeletionTimestamp,
+	}
+	for _, f := range p.Spec.Finalizers {
+		t.Finalizers = append(t.Finalizers, string(f))
+	}
+	t.Finalizers = append(t.Finalizers, p.Finalizers...)
+	for _, c := range p.Status.Conditions {
+		if c.Status == corev1.ConditionTrue {
+			t.Conditions = append(t.Conditions, c)
+		}
+	}
+	return t
+}
+
+func (t Terminating) String() string {
+	s := fmt.Sprintf("%s is still %s", t.Name, t.Phase)
+	if t.Since != nil {
+		s += fmt.Sprintf(" (deletion requested %s ago)", time.Since(t.Since.Time).Round(time.Second))
+	}
+	if len(t.Finalizers) > 0 {
+		s += "\n  finalizers: " + strings.Join(t.Finalizers, ", ")
+	}
+	for _, c := range t.Conditions {
+		s += fmt.Sprintf("\n  condition %s: %s %s", c.Type, c.Reason, c.Message)
+	}
+	return s
+}
+
+// NotDeletedError is returned when the wait ends before every project was
+// removed. It unwraps to the reason, e.g. context.DeadlineExceeded.
+type NotDeletedError struct {
+	Remaining []Terminating
+	Err       error
+}
+
+func (e *NotDeletedError) Error() string {
+	names := make([]string, len(e.Remaining))
+	for i, t := range e.Remaining {
+		names[i] = t.Name
+	}
+	return fmt.Sprintf("projects not deleted: %s: %v", strings.Join(names, ", "), e.Err)
+}
+
+func (e *NotDeletedError) Unwrap() error {
+	return e.Err
+}
+
+// Delete deletes the named projects. Projects that do not exist are
+// treated as deleted, and projects that are already Terminating, which the
+// server rejects with a Conflict, as pending deletion. With opts.Wait it
+// then waits for them to be gone.
+func Delete(ctx context.Context, client projectclientset.Interface, names []string, opts DeleteOptions) error {
+	var errs []error
+	var deleted []string
+	for _, name := range names {
+		err := client.ProjectV1().Projects().Delete(ctx, name, metav1.DeleteOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			if opts.OnDeleted != nil {
+				opts.OnDeleted(name)
+			}
+		case apierrors.IsConflict(err):
+			deleted = append(deleted, name)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		default:
+			deleted = append(deleted, name)
+		}
+	}
+
+	if opts.Wait {
+		if err := WaitForDeleted(ctx, client, deleted, opts); err != nil {
+			errs = append(errs, err)
+		}
+	} else if opts.OnDeleted != nil {
+		for _, name := range deleted {
+			opts.OnDeleted(name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deletion tracks which of a set of projects still exist.
+type deletion struct {
+	pending  map[string]*projectv1.Project
+	reported map[string]bool
+	started  time.Time
+	opts     DeleteOptions
+}
+
+func (d *deletion) gone(name string) {
+	if _, ok := d.pending[name]; !ok {
+		return
+	}
+	delete(d.pending, name)
+	if d.opts.OnDeleted != nil {
+		d.opts.OnDeleted(name)
+	}
+}
+
+func (d *deletion) seen(p *projectv1.Project) {
+	if _, ok := d.pending[p.Name]; ok {
+		d.pending[p.Name] = p
+	}
+}
+
+// check fetches every pending project and reports the ones that are stuck.
+func (d *deletion) check(ctx context.Context, client projectclientset.Interface) {
+	for _, name := range sortedKeys(d.pending) {
+		p, err := client.ProjectV1().Projects().Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			d.gone(name)
+			continue
+		case err == nil:
+			d.pending[name] = p
+		}
+
+		p = d.pending[name]
+		if p == nil || d.reported[name] || time.Since(d.started) < d.opts.StuckAfter {
+			continue
+		}
+		d.reported[name] = true
+		if d.opts.OnStuck != nil {
+			d.opts.OnStuck(terminatingState(p))
+		}
+	}
+}
+
+func (d *deletion) notDeleted(err error) error {
+	e := &NotDeletedError{Err: err}
+	for _, name := range sortedKeys(d.pending) {
+		if p := d.pending[name]; p != nil {
+			e.Remaining = append(e.Remaining, terminatingState(p))
+		} else {
+			e.Remaining = append(e.Remaining, Terminating{Name: name})
+		}
+	}
+	return e
+}
+
+// WaitForDeleted blocks until every project in names is gone. Deletions
+// are picked up from a watch; the pending projects are also fetched
+// periodically, which reports projects stuck in Terminating through
+// opts.OnStuck.
+func WaitForDeleted(ctx context.Context, client projectclientset.Interface, names []string, opts DeleteOptions) error {
+	if opts.StuckAfter <= 0 {
+		opts.StuckAfter = DefaultStuckAfter
+	}
+	d := &deletion{
+		pending:  map[string]*projectv1.Project{},
+		reported: map[string]bool{},
+		started:  time.Now(),
+		opts:     opts,
+	}
+	for _, name := range names {
+		d.pending[name] = nil
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	w := projectwatch.New(ctx, client, projectwatch.Options{})
+	defer w.Stop()
+
+	ticker := time.NewTicker(deleteCheckInterval)
+	defer ticker.Stop()
+
+	d.check(ctx, client)
+	for len(d.pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return d.notDeleted(ctx.Err())
+		case <-ticker.C:
+			d.check(ctx, client)
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return d.notDeleted(ctx.Err())
+			}
+			proj, skip, err := projectwatch.Next(event)
+			if err != nil {
+				return d.notDeleted(err)
+			}
+			if skip {
+				continue
+			}
+
+			if event.Type == watch.Deleted {
+				d.gone(proj.Name)
+			} else {
+				d.seen(proj)
+			}
+		}
+	}
+	return nil
+}
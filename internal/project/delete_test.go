@@ -0,0 +1,62 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectfake "github.com/openshift/client-go/project/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDeleteAlreadyTerminating(t *testing.T) {
+	now := metav1.Now()
+	stuck := &projectv1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "stuckproj",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{"example.com/cleanup"},
+		},
+		Spec:   projectv1.ProjectSpec{Finalizers: []corev1.FinalizerName{"kubernetes"}},
+		Status: projectv1.ProjectStatus{Phase: corev1.NamespaceTerminating},
+	}
+	client := projectfake.NewClientset(stuck)
+	client.PrependReactor("delete", "projects", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Group: projectv1.GroupName, Resource: "projects"},
+			"stuckproj", errors.New("The system is ensuring all content is removed from this namespace."))
+	})
+
+	var reports []Terminating
+	err := Delete(context.Background(), client, []string{"stuckproj"}, DeleteOptions{
+		Wait:       true,
+		Timeout:    200 * time.Millisecond,
+		StuckAfter: time.Nanosecond,
+		OnStuck:    func(t Terminating) { reports = append(reports, t) },
+	})
+
+	var notDeleted *NotDeletedError
+	if !errors.As(err, &notDeleted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Delete() error = %v; want a NotDeletedError after the timeout", err)
+	}
+	if apierrors.IsConflict(err) {
+		t.Errorf("Delete() error = %v; the conflict should have been treated as pending deletion", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("OnStuck called %d times; want 1", len(reports))
+	}
+	want := []string{"kubernetes", "example.com/cleanup"}
+	if got := reports[0].Finalizers; !reflect.DeepEqual(got, want) {
+		t.Errorf("OnStuck finalizers = %v; want %v", got, want)
+	}
+	if reports[0].Phase != corev1.NamespaceTerminating {
+		t.Errorf("OnStuck phase = %s; want Terminating", reports[0].Phase)
+	}
+}
@@ -0,0 +1,79 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataDiff renders a unified diff between the labels and annotations
+// of a project before and after a change. Each label or annotation is one
+// "label key=value" or "annotation key=value" line, so the whole diff is a
+// single hunk with full context. It returns "" when nothing changes.
+func MetadataDiff(name string, beforeLabels, beforeAnnotations, afterLabels, afterAnnotations map[string]string) string {
+	before := metadataLines(beforeLabels, beforeAnnotations)
+	after := metadataLines(afterLabels, afterAnnotations)
+
+	var (
+		body    []string
+		changed bool
+		i, j    int
+	)
+	for i < len(before) || j < len(after) {
+		switch {
+		case j == len(after) || (i < len(before) && lineKey(before[i]) < lineKey(after[j])):
+			body = append(body, "-"+before[i])
+			changed = true
+			i++
+		case i == len(before) || lineKey(after[j]) < lineKey(before[i]):
+			body = append(body, "+"+after[j])
+			changed = true
+			j++
+		case before[i] == after[j]:
+			body = append(body, " "+before[i])
+			i++
+			j++
+		default:
+			body = append(body, "-"+before[i], "+"+after[j])
+			changed = true
+			i++
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s (current)\n", name)
+	fmt.Fprintf(&b, "+++ %s (desired)\n", name)
+	fmt.Fprintf(&b, "@@ %s %s @@\n", hunkRange("-", len(before)), hunkRange("+", len(after)))
+	for _, line := range body {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func metadataLines(labels, annotations map[string]string) []string {
+	lines := make([]string, 0, len(labels)+len(annotations))
+	for _, k := range sortedKeys(annotations) {
+		lines = append(lines, fmt.Sprintf("annotation %s=%s", k, annotations[k]))
+	}
+	for _, k := range sortedKeys(labels) {
+		lines = append(lines, fmt.Sprintf("label %s=%s", k, labels[k]))
+	}
+	return lines
+}
+
+// lineKey is the part of a metadata line that identifies it.
+func lineKey(line string) string {
+	key, _, _ := strings.Cut(line, "=")
+	return key
+}
+
+func hunkRange(sign string, n int) string {
+	if n == 0 {
+		return sign + "0,0"
+	}
+	return fmt.Sprintf("%s1,%d", sign, n)
+}
@@ -0,0 +1,200 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Preview is the predicted effect of an Action.
+type Preview struct {
+	Action Action
+	// ServerSide is true when the API server accepted the change as a dry
+	// run, false when it was only computed locally.
+	ServerSide bool
+	// Diff is the unified diff of labels and annotations.
+	Diff string
+	Err  error
+}
+
+func (p Preview) String() string {
+	mode := "client dry run"
+	if p.ServerSide {
+		mode = "server dry run"
+	}
+
+	s := p.Action.Name + " " + string(p.Action.Type) + " (" + mode + ")"
+	if p.Err != nil {
+		return s + ": would fail: " + p.Err.Error()
+	}
+	if p.Diff != "" {
+		s += "\n" + strings.TrimSuffix(p.Diff, "\n")
+	}
+	return s
+}
+
+// DryRun previews actions without changing the cluster. Every action is
+// first sent with DryRun set to All; when the API server does not support
+// dry run for the resource, as with ProjectRequests, the preview is
+// computed on the client from the action alone. Each resource is probed
+// once; later actions on an unsupported resource skip the server.
+func DryRun(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, actions []Action) []Preview {
+	r := &dryRunner{unsupported: map[string]bool{}}
+	previews := make([]Preview, 0, len(actions))
+	for _, a := range actions {
+		previews = append(previews, r.dryRunOne(ctx, client, kube, a))
+	}
+	return previews
+}
+
+// dryRunner sends dry-run requests and remembers the resources that
+// rejected them.
+type dryRunner struct {
+	unsupported map[string]bool
+}
+
+// do sends a dry-run request for resource unless it is known not to
+// support dry run. It returns false when the server refused the dry run,
+// and the error of a request that was otherwise answered.
+func (r *dryRunner) do(resource string, call func() error) (bool, error) {
+	if r.unsupported[resource] {
+		return false, nil
+	}
+	err := call()
+	if isDryRunUnsupported(err) {
+		r.unsupported[resource] = true
+		return false, nil
+	}
+	return true, err
+}
+
+func (r *dryRunner) dryRunOne(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, a Action) Preview {
+	preview := Preview{Action: a}
+	dryRun := []string{metav1.DryRunAll}
+
+	var beforeLabels, beforeAnnotations map[string]string
+	if a.Current != nil {
+		beforeLabels, beforeAnnotations = a.Current.Labels, a.Current.Annotations
+	}
+	afterLabels, afterAnnotations := desiredMetadata(a)
+
+	var (
+		serverSide bool
+		err        error
+	)
+	switch a.Type {
+	case ActionCreate:
+		serverSide, err = r.do("projectrequests", func() error {
+			p, err := client.ProjectV1().ProjectRequests().Create(ctx, a.Spec.ProjectRequest(), metav1.CreateOptions{DryRun: dryRun})
+			if err == nil {
+				// The server fills in annotations such as the requester.
+				afterAnnotations = mergeMaps(p.Annotations, afterAnnotations)
+			}
+			return err
+		})
+	case ActionUpdate:
+		serverSide, err = r.dryRunUpdate(ctx, client, kube, a, dryRun)
+	case ActionDelete:
+		serverSide, err = r.do("projects", func() error {
+			return client.ProjectV1().Projects().Delete(ctx, a.Name, metav1.DeleteOptions{DryRun: dryRun})
+		})
+	}
+	if err != nil {
+		preview.Err = err
+		return preview
+	}
+	preview.ServerSide = serverSide
+
+	preview.Diff = MetadataDiff(a.Name, beforeLabels, beforeAnnotations, afterLabels, afterAnnotations)
+	return preview
+}
+
+// dryRunUpdate dry-runs the project patch and the namespace update of a.
+// The preview is server side only if every request was.
+func (r *dryRunner) dryRunUpdate(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, a Action, dryRun []string) (bool, error) {
+	serverSide := true
+	if len(Drift(a.Spec, a.Current)) > 0 {
+		patch, err := displayPatch(a.Spec)
+		if err != nil {
+			return false, err
+		}
+		ok, err := r.do("projects", func() error {
+			_, err := client.ProjectV1().Projects().Patch(ctx, a.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		serverSide = serverSide && ok
+	}
+
+	if kube != nil && (len(a.Spec.Labels) > 0 || len(a.Spec.NamespaceAnnotations()) > 0) {
+		ok, err := r.do("namespaces", func() error {
+			return applyMetadata(ctx, kube, a.Spec, metav1.UpdateOptions{DryRun: dryRun})
+		})
+		if err != nil {
+			return false, err
+		}
+		serverSide = serverSide && ok
+	}
+	return serverSide, nil
+}
+
+// desiredMetadata returns the labels and annotations a project will have
+// once a is applied.
+func desiredMetadata(a Action) (map[string]string, map[string]string) {
+	if a.Type == ActionDelete {
+		return nil, nil
+	}
+
+	annotations := mergeMaps(a.Spec.NamespaceAnnotations(), map[string]string{
+		AnnotationDisplayName: a.Spec.DisplayName,
+		AnnotationDescription: a.Spec.Description,
+	})
+	if a.Current == nil {
+		return a.Spec.Labels, annotations
+	}
+	return mergeMaps(a.Current.Labels, a.Spec.Labels), mergeMaps(a.Current.Annotations, annotations)
+}
+
+// mergeMaps returns a new map with the entries of base overridden by those
+// of overrides.
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// isDryRunUnsupported reports whether err rejects the dry-run option
+// itself: a 405 MethodNotSupported, or a 400 BadRequest whose status
+// details name the dryRun field. Any other BadRequest is a real failure of
+// the request.
+func isDryRunUnsupported(err error) bool {
+	if apierrors.IsMethodNotSupported(err) {
+		return true
+	}
+	if !apierrors.IsBadRequest(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Field == "dryRun" {
+			return true
+		}
+	}
+	return false
+}
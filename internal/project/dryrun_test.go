@@ -0,0 +1,64 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectfake "github.com/openshift/client-go/project/clientset/versioned/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDryRunUnsupported(t *testing.T) {
+	dryRunRejected := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    400,
+		Reason:  metav1.StatusReasonBadRequest,
+		Message: "dry run is not supported",
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{Field: "dryRun"}}},
+	}}
+
+	tests := []struct {
+		name       string
+		err        error
+		serverSide bool
+		failed     bool
+		// calls is the number of create requests for two actions.
+		calls int
+	}{
+		{"accepted", nil, true, false, 2},
+		{"method not supported", apierrors.NewMethodNotSupported(projectv1.Resource("projectrequests"), "create"), false, false, 1},
+		{"bad request naming dryRun", dryRunRejected, false, false, 1},
+		{"other bad request", apierrors.NewBadRequest("malformed request"), false, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := projectfake.NewClientset()
+			calls := 0
+			client.PrependReactor("create", "projectrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				calls++
+				if tt.err != nil {
+					return true, nil, tt.err
+				}
+				pr := action.(clienttesting.CreateAction).GetObject().(*projectv1.ProjectRequest)
+				return true, &projectv1.Project{ObjectMeta: pr.ObjectMeta}, nil
+			})
+
+			actions := []Action{
+				{Type: ActionCreate, Name: "a", Spec: Spec{Name: "a"}},
+				{Type: ActionCreate, Name: "b", Spec: Spec{Name: "b"}},
+			}
+			for _, p := range DryRun(context.Background(), client, nil, actions) {
+				if p.ServerSide != tt.serverSide || (p.Err != nil) != tt.failed {
+					t.Errorf("%s: ServerSide = %v, Err = %v; want %v, failed %v", p.Action.Name, p.ServerSide, p.Err, tt.serverSide, tt.failed)
+				}
+			}
+			if calls != tt.calls {
+				t.Errorf("create requests = %d; want %d", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package project
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strings"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectlisters "github.com/openshift/client-go/project/listers/project/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Field selector keys supported by the Project API.
+const (
+	FieldName  = "metadata.name"
+	FieldPhase = "status.phase"
+)
+
+// Filter selects projects. Label and field selectors are sent to the API
+// server; the name and requester filters are applied on the client. The
+// same filter gives the same result when evaluated against a lister.
+type Filter struct {
+	LabelSelector string
+	FieldSelector string
+	NamePrefix    string
+	NameRegex     string
+	Requester     string
+}
+
+// AddFlags registers the filter flags on fs.
+func (f *Filter) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.LabelSelector, "selector", f.LabelSelector, "label selector, e.g. team=a,env!=prod")
+	fs.StringVar(&f.FieldSelector, "field-selector", f.FieldSelector, "field selector on metadata.name or status.phase, e.g. status.phase=Active")
+	fs.StringVar(&f.NamePrefix, "name-prefix", f.NamePrefix, "only projects whose name starts with this prefix")
+	fs.StringVar(&f.NameRegex, "name-regex", f.NameRegex, "only projects whose name matches this regular expression")
+	fs.StringVar(&f.Requester, "requester", f.Requester, "only projects requested by this user")
+}
+
+// Matcher is a compiled Filter.
+type Matcher struct {
+	labels    labels.Selector
+	fields    fields.Selector
+	prefix    string
+	re        *regexp.Regexp
+	requester string
+}
+
+func (f Filter) Compile() (*Matcher, error) {
+	m := &Matcher{
+		labels:    labels.Everything(),
+		fields:    fields.Everything(),
+		prefix:    f.NamePrefix,
+		requester: f.Requester,
+	}
+
+	var err error
+	if f.LabelSelector != "" {
+		if m.labels, err = labels.Parse(f.LabelSelector); err != nil {
+			return nil, fmt.Errorf("label selector %q: %w", f.LabelSelector, err)
+		}
+	}
+	if f.FieldSelector != "" {
+		if m.fields, err = fields.ParseSelector(f.FieldSelector); err != nil {
+			return nil, fmt.Errorf("field selector %q: %w", f.FieldSelector, err)
+		}
+		for _, r := range m.fields.Requirements() {
+			if r.Field != FieldName && r.Field != FieldPhase {
+				return nil, fmt.Errorf("field selector %q: unsupported field %q, expected %s or %s", f.FieldSelector, r.Field, FieldName, FieldPhase)
+			}
+		}
+	}
+	if f.NameRegex != "" {
+		if m.re, err = regexp.Compile(f.NameRegex); err != nil {
+			return nil, fmt.Errorf("name regex %q: %w", f.NameRegex, err)
+		}
+	}
+	return m, nil
+}
+
+// ListOptions returns the server-side part of the filter.
+func (m *Matcher) ListOptions() metav1.ListOptions {
+	opts := metav1.ListOptions{}
+	if !m.labels.Empty() {
+		opts.LabelSelector = m.labels.String()
+	}
+	if !m.fields.Empty() {
+		opts.FieldSelector = m.fields.String()
+	}
+	return opts
+}
+
+// Matches evaluates the whole filter, including the selectors, against p.
+func (m *Matcher) Matches(p *projectv1.Project) bool {
+	if !m.labels.Matches(labels.Set(p.Labels)) {
+		return false
+	}
+	if !m.fields.Matches(fields.Set{FieldName: p.Name, FieldPhase: string(p.Status.Phase)}) {
+		return false
+	}
+	if m.prefix != "" && !strings.HasPrefix(p.Name, m.prefix) {
+		return false
+	}
+	if m.re != nil && !m.re.MatchString(p.Name) {
+		return false
+	}
+	if m.requester != "" && Requester(p) != m.requester {
+		return false
+	}
+	return true
+}
+
+// ListFromLister lists the projects matching m from an informer cache.
+func ListFromLister(lister projectlisters.ProjectLister, m *Matcher) ([]*projectv1.Project, error) {
+	list, err := lister.List(m.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*projectv1.Project
+	for _, p := range list {
+		if m.Matches(p) {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
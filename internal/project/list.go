@@ -0,0 +1,83 @@
+package project
+
+import (
+	"context"
+	"log"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultPageSize is the number of projects requested per page.
+const DefaultPageSize = 500
+
+// List lists the projects matching m from the API server, DefaultPageSize
+// projects at a time.
+func List(ctx context.Context, client projectclientset.Interface, m *Matcher) ([]projectv1.Project, error) {
+	var projects []projectv1.Project
+	err := ListPages(ctx, client, m, DefaultPageSize, func(page []projectv1.Project) error {
+		projects = append(projects, page...)
+		return nil
+	})
+	return projects, err
+}
+
+// ListPages lists the projects matching m in pages of pageSize and calls fn
+// with the matching projects of each page as it arrives. A pageSize of 0
+// lists everything in one request.
+//
+// When the continue token expires between pages (410 Gone), the listing is
+// restarted from the beginning and projects up to the last one already
+// passed to fn are skipped. Lists are ordered by name, so fn sees every
+// project at most once and in order.
+//
+// The Project API answers lists from the project authorization cache of
+// the OpenShift API server, which may ignore Limit and Continue. All
+// projects visible to the user then arrive in a single page, and pageSize
+// bounds neither the response size nor its duration.
+func ListPages(ctx context.Context, client projectclientset.Interface, m *Matcher, pageSize int64, fn func([]projectv1.Project) error) error {
+	opts := m.ListOptions()
+	opts.Limit = pageSize
+
+	last := ""
+	for {
+		list, err := client.ProjectV1().Projects().List(ctx, opts)
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+			if opts.Continue == "" {
+				return err
+			}
+			log.Printf("Continue token expired after %q, restarting the list", last)
+			opts.Continue = ""
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		page := make([]projectv1.Project, 0, len(list.Items))
+		for i := range list.Items {
+			p := &list.Items[i]
+			if last != "" && p.Name <= last {
+				continue
+			}
+			if m.Matches(p) {
+				page = append(page, *p)
+			}
+		}
+		if n := len(list.Items); n > 0 && list.Items[n-1].Name > last {
+			last = list.Items[n-1].Name
+		}
+
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+
+		if list.Continue == "" {
+			return nil
+		}
+		opts.Continue = list.Continue
+	}
+}
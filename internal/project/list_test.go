@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectfake "github.com/openshift/client-go/project/clientset/versioned/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// listResponse is what the fake server answers to one list request.
+type listResponse struct {
+	names []string
+	cont  string
+	err   error
+}
+
+func TestListPages(t *testing.T) {
+	expired := apierrors.NewResourceExpired("continue token expired")
+
+	tests := []struct {
+		name      string
+		responses []listResponse
+		// continues are the continue tokens the client is expected to send.
+		continues []string
+		want      [][]string
+		wantErr   bool
+	}{
+		{
+			name: "pages",
+			responses: []listResponse{
+				{names: []string{"a", "b"}, cont: "t1"},
+				{names: []string{"c"}},
+			},
+			continues: []string{"", "t1"},
+			want:      [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name: "restart after expired token",
+			responses: []listResponse{
+				{names: []string{"a", "b"}, cont: "t1"},
+				{err: expired},
+				{names: []string{"a", "b"}, cont: "t2"},
+				{names: []string{"c", "d"}},
+			},
+			continues: []string{"", "t1", "", "t2"},
+			want:      [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name: "restart skips projects already seen",
+			responses: []listResponse{
+				{names: []string{"a", "b"}, cont: "t1"},
+				{err: expired},
+				{names: []string{"a", "c"}},
+			},
+			continues: []string{"", "t1", ""},
+			want:      [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name:      "expired on the first page",
+			responses: []listResponse{{err: expired}},
+			continues: []string{""},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := projectfake.NewClientset()
+			var continues []string
+			client.PrependReactor("list", "projects", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				opts := action.(clienttesting.ListActionImpl).GetListOptions()
+				continues = append(continues, opts.Continue)
+				if opts.Limit != 2 {
+					t.Errorf("Limit = %d; want 2", opts.Limit)
+				}
+				resp := tt.responses[len(continues)-1]
+				if resp.err != nil {
+					return true, nil, resp.err
+				}
+				list := &projectv1.ProjectList{ListMeta: metav1.ListMeta{Continue: resp.cont}}
+				for _, name := range resp.names {
+					list.Items = append(list.Items, projectv1.Project{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				}
+				return true, list, nil
+			})
+
+			m, err := Filter{}.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [][]string
+			err = ListPages(context.Background(), client, m, 2, func(page []projectv1.Project) error {
+				var names []string
+				for _, p := range page {
+					names = append(names, p.Name)
+				}
+				got = append(got, names)
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListPages() error = %v; want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pages = %v; want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(continues, tt.continues) {
+				t.Errorf("continue tokens sent = %q; want %q", continues, tt.continues)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package project contains the Project operations shared by the demos and
+// the ocproj command.
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	AnnotationDisplayName  = "openshift.io/display-name"
+	AnnotationDescription  = "openshift.io/description"
+	AnnotationRequester    = "openshift.io/requester"
+	AnnotationNodeSelector = "openshift.io/node-selector"
+)
+
+// Spec describes a project to be requested. Labels, Annotations, Requester
+// and NodeSelector are not part of a ProjectRequest and are applied to the
+// namespace after creation.
+type Spec struct {
+	Name         string
+	DisplayName  string
+	Description  string
+	Labels       map[string]string
+	Annotations  map[string]string
+	Requester    string
+	NodeSelector string
+}
+
+func NewProjectRequest(name string, displayName string, description string) *projectv1.ProjectRequest {
+	projectRequest := projectv1.ProjectRequest{
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		DisplayName: displayName,
+		Description: description,
+	}
+	return &projectRequest
+}
+
+func (s Spec) ProjectRequest() *projectv1.ProjectRequest {
+	return NewProjectRequest(s.Name, s.DisplayName, s.Description)
+}
+
+// NamespaceAnnotations returns Annotations plus the annotations backing
+// Requester and NodeSelector.
+func (s Spec) NamespaceAnnotations() map[string]string {
+	if len(s.Annotations) == 0 && s.Requester == "" && s.NodeSelector == "" {
+		return nil
+	}
+
+	annotations := map[string]string{}
+	for k, v := range s.Annotations {
+		annotations[k] = v
+	}
+	if s.Requester != "" {
+		annotations[AnnotationRequester] = s.Requester
+	}
+	if s.NodeSelector != "" {
+		annotations[AnnotationNodeSelector] = s.NodeSelector
+	}
+	return annotations
+}
+
+// Validate reports every problem with s: the name must be a DNS-1123
+// label and labels and annotations must be valid Kubernetes metadata.
+func (s Spec) Validate() []string {
+	var problems []string
+
+	if s.Name == "" {
+		problems = append(problems, "name is required")
+	} else {
+		for _, msg := range validation.IsDNS1123Label(s.Name) {
+			problems = append(problems, fmt.Sprintf("name %q: %s", s.Name, msg))
+		}
+	}
+	for k, v := range s.Labels {
+		for _, msg := range validation.IsQualifiedName(k) {
+			problems = append(problems, fmt.Sprintf("label key %q: %s", k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			problems = append(problems, fmt.Sprintf("label %s value %q: %s", k, v, msg))
+		}
+	}
+	for k := range s.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(k)) {
+			problems = append(problems, fmt.Sprintf("annotation key %q: %s", k, msg))
+		}
+	}
+	if s.NodeSelector != "" {
+		if _, err := labels.Parse(s.NodeSelector); err != nil {
+			problems = append(problems, fmt.Sprintf("node selector %q: %v", s.NodeSelector, err))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func DisplayName(p *projectv1.Project) string {
+	return p.Annotations[AnnotationDisplayName]
+}
+
+func Description(p *projectv1.Project) string {
+	return p.Annotations[AnnotationDescription]
+}
+
+func Requester(p *projectv1.Project) string {
+	return p.Annotations[AnnotationRequester]
+}
+
+// ParseKeyValues parses "k1=v1,k2=v2" into a map. An empty string yields a
+// nil map.
+func ParseKeyValues(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	m := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		m[k] = strings.TrimSpace(v)
+	}
+	return m, nil
+}
+
+// FormatKeyValues is the inverse of ParseKeyValues, with keys sorted.
+func FormatKeyValues(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+m[k])
+	}
+	return strings.Join(pairs, ",")
+}
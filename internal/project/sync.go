@@ -0,0 +1,223 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ActionType is what Sync does to a single project.
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Action is one planned change. Spec is empty for deletions and Current is
+// nil for creations.
+type Action struct {
+	Type    ActionType
+	Name    string
+	Spec    Spec
+	Current *projectv1.Project
+	// Changes describes the fields an update modifies.
+	Changes []string
+	// Err is set by ApplySync when the action failed.
+	Err error
+}
+
+func (a Action) String() string {
+	s := fmt.Sprintf("%s %s", a.Name, a.Type)
+	for _, c := range a.Changes {
+		s += "\n  " + c
+	}
+	if a.Err != nil {
+		s += fmt.Sprintf(": failed: %v", a.Err)
+	}
+	return s
+}
+
+// SyncOptions controls PlanSync and ApplySync.
+type SyncOptions struct {
+	// ManagedSelector is a single key=value label that Sync puts on every
+	// project it creates or updates. Prune only considers projects that
+	// carry it.
+	ManagedSelector string
+	// Prune deletes managed projects that are no longer declared.
+	Prune bool
+	// OnAction, if set, is called after each action has been applied.
+	OnAction func(Action)
+}
+
+func (o SyncOptions) managedLabel() (map[string]string, error) {
+	if o.ManagedSelector == "" {
+		return nil, nil
+	}
+
+	set, err := labels.ConvertSelectorToLabelsMap(o.ManagedSelector)
+	if err != nil {
+		return nil, fmt.Errorf("managed selector %q: %w", o.ManagedSelector, err)
+	}
+	if len(set) != 1 {
+		return nil, fmt.Errorf("managed selector %q must be a single key=value label", o.ManagedSelector)
+	}
+	return set, nil
+}
+
+// PlanSync compares specs with the projects in the cluster and returns the
+// actions needed to converge, creations first, then updates and deletions,
+// each sorted by name.
+func PlanSync(ctx context.Context, client projectclientset.Interface, specs []Spec, opts SyncOptions) ([]Action, error) {
+	managed, err := opts.managedLabel()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Prune && managed == nil {
+		return nil, fmt.Errorf("prune requires a managed selector")
+	}
+
+	list, err := client.ProjectV1().Projects().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*projectv1.Project{}
+	for i := range list.Items {
+		existing[list.Items[i].Name] = &list.Items[i]
+	}
+
+	var actions []Action
+	declared := map[string]bool{}
+	for _, spec := range specs {
+		spec = withLabels(spec, managed)
+		declared[spec.Name] = true
+
+		current, ok := existing[spec.Name]
+		if !ok {
+			actions = append(actions, Action{Type: ActionCreate, Name: spec.Name, Spec: spec})
+			continue
+		}
+		if changes := syncChanges(spec, current); len(changes) > 0 {
+			actions = append(actions, Action{Type: ActionUpdate, Name: spec.Name, Spec: spec, Current: current, Changes: changes})
+		}
+	}
+
+	if opts.Prune {
+		selector := labels.SelectorFromSet(managed)
+		for name, p := range existing {
+			if !declared[name] && selector.Matches(labels.Set(p.Labels)) {
+				actions = append(actions, Action{Type: ActionDelete, Name: name, Current: p})
+			}
+		}
+	}
+
+	order := map[ActionType]int{ActionCreate: 0, ActionUpdate: 1, ActionDelete: 2}
+	sort.SliceStable(actions, func(i, j int) bool {
+		if actions[i].Type != actions[j].Type {
+			return order[actions[i].Type] < order[actions[j].Type]
+		}
+		return actions[i].Name < actions[j].Name
+	})
+	return actions, nil
+}
+
+// ApplySync executes actions in order. It keeps going after failures and
+// returns the actions with Err filled in.
+func ApplySync(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, actions []Action, opts SyncOptions) []Action {
+	applied := make([]Action, 0, len(actions))
+	for _, a := range actions {
+		switch a.Type {
+		case ActionCreate:
+			_, a.Err = Create(ctx, client, kube, a.Spec, CreateOptions{})
+		case ActionUpdate:
+			a.Err = update(ctx, client, kube, a.Spec, a.Current)
+		case ActionDelete:
+			a.Err = client.ProjectV1().Projects().Delete(ctx, a.Name, metav1.DeleteOptions{})
+		}
+
+		applied = append(applied, a)
+		if opts.OnAction != nil {
+			opts.OnAction(a)
+		}
+	}
+	return applied
+}
+
+// SyncFailures counts the actions that failed.
+func SyncFailures(actions []Action) int {
+	n := 0
+	for _, a := range actions {
+		if a.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func withLabels(spec Spec, extra map[string]string) Spec {
+	if len(extra) == 0 {
+		return spec
+	}
+
+	spec.Labels = mergeMaps(spec.Labels, extra)
+	return spec
+}
+
+func syncChanges(spec Spec, current *projectv1.Project) []string {
+	changes := Drift(spec, current)
+	for _, k := range sortedKeys(spec.Labels) {
+		if got, ok := current.Labels[k]; !ok || got != spec.Labels[k] {
+			changes = append(changes, fmt.Sprintf("label %s is %q, want %q", k, got, spec.Labels[k]))
+		}
+	}
+	annotations := spec.NamespaceAnnotations()
+	for _, k := range sortedKeys(annotations) {
+		if got, ok := current.Annotations[k]; !ok || got != annotations[k] {
+			changes = append(changes, fmt.Sprintf("annotation %s is %q, want %q", k, got, annotations[k]))
+		}
+	}
+	return changes
+}
+
+// update patches the display name and description through the Project API,
+// which allows changing them, and the remaining labels and annotations
+// through the namespace.
+func update(ctx context.Context, client projectclientset.Interface, kube kubernetes.Interface, spec Spec, current *projectv1.Project) error {
+	if len(Drift(spec, current)) > 0 {
+		patch, err := displayPatch(spec)
+		if err != nil {
+			return err
+		}
+		_, err = client.ProjectV1().Projects().Patch(ctx, spec.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(spec.Labels) > 0 || len(spec.NamespaceAnnotations()) > 0 {
+		return applyMetadata(ctx, kube, spec, metav1.UpdateOptions{})
+	}
+	return nil
+}
+
+// displayPatch is a merge patch setting the display name and description
+// annotations, the only ones the Project API lets users change.
+func displayPatch(spec Spec) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				AnnotationDisplayName: spec.DisplayName,
+				AnnotationDescription: spec.Description,
+			},
+		},
+	})
+}
@@ -0,0 +1,178 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventhandler"
+	"github.com/fminamot/openshift-clientgo-demo/internal/projectwatch"
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WaitOptions controls WaitForActive and WaitForActiveInformer.
+type WaitOptions struct {
+	// Timeout bounds the wait. Zero means wait until ctx is done.
+	Timeout time.Duration
+	// OnReady, if set, is called once for every project that becomes
+	// Active, with the number of projects ready so far.
+	OnReady func(p *projectv1.Project, ready, total int)
+}
+
+// NotReadyError is returned when the wait ends before every project became
+// Active. It unwraps to the reason, e.g. context.DeadlineExceeded.
+type NotReadyError struct {
+	NotReady []string
+	Err      error
+}
+
+func (e *NotReadyError) Error() string {
+	return fmt.Sprintf("projects not active: %s: %v", strings.Join(e.NotReady, ", "), e.Err)
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.Err
+}
+
+// readiness tracks which of a set of projects are Active.
+type readiness struct {
+	pending map[string]bool
+	total   int
+	opts    WaitOptions
+}
+
+func newReadiness(names []string, opts WaitOptions) *readiness {
+	r := &readiness{pending: map[string]bool{}, opts: opts}
+	for _, name := range names {
+		r.pending[name] = true
+	}
+	r.total = len(r.pending)
+	return r
+}
+
+// observe records p and reports whether every project is now Active.
+func (r *readiness) observe(p *projectv1.Project) bool {
+	if r.pending[p.Name] && p.Status.Phase == corev1.NamespaceActive {
+		delete(r.pending, p.Name)
+		if r.opts.OnReady != nil {
+			r.opts.OnReady(p, r.total-len(r.pending), r.total)
+		}
+	}
+	return r.done()
+}
+
+func (r *readiness) done() bool {
+	return len(r.pending) == 0
+}
+
+func (r *readiness) notReady(err error) error {
+	return &NotReadyError{NotReady: sortedKeys(r.pending), Err: err}
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// WaitForActive blocks until every project in names reaches the Active
+// phase, using a resilient watch that starts with a list of the current
+// projects.
+func WaitForActive(ctx context.Context, client projectclientset.Interface, names []string, opts WaitOptions) error {
+	r := newReadiness(names, opts)
+	if r.done() {
+		return nil
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	w := projectwatch.New(ctx, client, projectwatch.Options{})
+	defer w.Stop()
+
+	for event := range w.ResultChan() {
+		proj, skip, err := projectwatch.Next(event)
+		if err != nil {
+			return r.notReady(err)
+		}
+		if skip {
+			continue
+		}
+		if (event.Type == watch.Added || event.Type == watch.Modified) && r.observe(proj) {
+			return nil
+		}
+	}
+	return r.notReady(ctx.Err())
+}
+
+// WaitForActiveInformer is WaitForActive driven by a running, shared
+// project informer instead of a dedicated watch. OnReady is called from
+// the informer's event handler and should return quickly.
+func WaitForActiveInformer(ctx context.Context, informer cache.SharedIndexInformer, names []string, opts WaitOptions) error {
+	r := newReadiness(names, opts)
+	if r.done() {
+		return nil
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	// The handler checks readiness itself, under mu, so it never waits
+	// for this goroutine and cannot stall the informer's listener.
+	var mu sync.Mutex
+	ready := make(chan struct{})
+	observe := func(p *projectv1.Project) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !r.done() && r.observe(p) {
+			close(ready)
+		}
+	}
+	notReady := func(err error) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.notReady(err)
+	}
+
+	registration, err := informer.AddEventHandler(eventhandler.ProjectHandlerFuncs{
+		AddFunc:    func(p *projectv1.Project, _ bool) { observe(p) },
+		UpdateFunc: func(_, newP *projectv1.Project) { observe(newP) },
+	})
+	if err != nil {
+		return notReady(err)
+	}
+	defer informer.RemoveEventHandler(registration)
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return notReady(fmt.Errorf("informer cache not synced: %w", ctx.Err()))
+	}
+	for _, obj := range informer.GetStore().List() {
+		if p, ok := obj.(*projectv1.Project); ok {
+			observe(p)
+		}
+	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return notReady(ctx.Err())
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
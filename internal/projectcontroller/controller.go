@@ -0,0 +1,132 @@
+// Package projectcontroller implements a controller that fills in a
+// missing display name on projects.
+package projectcontroller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/fminamot/openshift-clientgo-demo/internal/controller"
+	"github.com/fminamot/openshift-clientgo-demo/internal/eventhandler"
+	"github.com/fminamot/openshift-clientgo-demo/internal/printer"
+	"github.com/fminamot/openshift-clientgo-demo/internal/project"
+	apiprojectv1 "github.com/openshift/api/project/v1"
+
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	projectinformersv1 "github.com/openshift/client-go/project/informers/externalversions/project/v1"
+	projectv1 "github.com/openshift/client-go/project/listers/project/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type ProjectController struct {
+	client     projectclientset.Interface
+	projLister projectv1.ProjectLister
+	controller *controller.Controller[*apiprojectv1.Project]
+}
+
+// DefaultPredicates passes adds, deletes and the updates the controller
+// acts on: changes to the display-name or requester annotations and phase
+// transitions. Resyncs and other metadata or status changes are dropped.
+func DefaultPredicates() []eventhandler.Predicate[*apiprojectv1.Project] {
+	return []eventhandler.Predicate[*apiprojectv1.Project]{
+		eventhandler.Or(
+			eventhandler.AnnotationChanged[*apiprojectv1.Project](project.AnnotationDisplayName, project.AnnotationRequester),
+			eventhandler.PhaseTransition(),
+		),
+	}
+}
+
+// Predicates returns DefaultPredicates, restricted to the projects whose
+// name matches nameRegex and whose labels match selector. Empty arguments
+// add no restriction.
+func Predicates(nameRegex, selector string) ([]eventhandler.Predicate[*apiprojectv1.Project], error) {
+	predicates := DefaultPredicates()
+	if nameRegex != "" {
+		re, err := regexp.Compile(nameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("name regex %q: %w", nameRegex, err)
+		}
+		predicates = append(predicates, eventhandler.NameMatches[*apiprojectv1.Project](re))
+	}
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("label selector %q: %w", selector, err)
+		}
+		predicates = append(predicates, eventhandler.LabelSelector[*apiprojectv1.Project](sel))
+	}
+	return predicates, nil
+}
+
+// NewProjectController returns a controller that reconciles the projects
+// whose events pass opts.Predicates, usually DefaultPredicates, optionally
+// combined with name or label filters.
+func NewProjectController(cl projectclientset.Interface, informer projectinformersv1.ProjectInformer, opts controller.Options[*apiprojectv1.Project]) (*ProjectController, error) {
+	if opts.Name == "" {
+		opts.Name = "project-display-name"
+	}
+
+	c := &ProjectController{
+		client:     cl,
+		projLister: informer.Lister(),
+	}
+
+	var err error
+	c.controller, err = controller.New(informer.Informer(), c.reconcile, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func printProject(p *apiprojectv1.Project) {
+	log.Printf("[worker] %s\n", printer.Summary(p))
+}
+
+func (c *ProjectController) reconcile(ctx context.Context, key string) (controller.Result, error) {
+	p, err := c.projLister.Get(key)
+
+	if errors.IsNotFound(err) {
+		log.Printf("%s not found in the cache\n", key)
+		return controller.Result{}, nil
+	}
+	if err != nil {
+		return controller.Result{}, fmt.Errorf("getting the project: %w", err)
+	}
+
+	printProject(p)
+
+	// display-nameが設定されていなければ、"<requester>'s <project>"という文字列をセット
+	dn := p.Annotations[project.AnnotationDisplayName]
+	if dn == "" {
+		newObj := p.DeepCopy()
+		if newObj.Annotations == nil {
+			newObj.Annotations = map[string]string{}
+		}
+		req := p.Annotations[project.AnnotationRequester]
+		newObj.Annotations[project.AnnotationDisplayName] = req + "'s " + newObj.Name
+		_, err := c.client.ProjectV1().Projects().Update(ctx, newObj, metav1.UpdateOptions{})
+		if errors.IsConflict(err) {
+			// The cache is behind; try again once it has the new version.
+			return controller.Result{Requeue: true}, nil
+		}
+		return controller.Result{}, err
+	}
+
+	return controller.Result{}, nil
+}
+
+func (c *ProjectController) Run(ctx context.Context) error {
+	log.Println("Ctrl-C will stop this controller")
+	if err := c.controller.Run(ctx); err != nil {
+		return err
+	}
+
+	log.Println("Controller done")
+	return nil
+}
@@ -0,0 +1,96 @@
+package projectwatch
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// EventError describes a watch.Error event, or an event whose object is
+// not a Project. It unwraps to an *apierrors.StatusError when the server
+// sent a Status, so apierrors.IsForbidden and friends work on it.
+type EventError struct {
+	Type   watch.EventType
+	Object runtime.Object
+	Status *metav1.Status
+}
+
+func (e *EventError) Error() string {
+	if e.Status != nil {
+		return fmt.Sprintf("watch error: %s (reason: %s, code: %d)", e.Status.Message, e.Status.Reason, e.Status.Code)
+	}
+	return fmt.Sprintf("unexpected object %T in %s event", e.Object, e.Type)
+}
+
+func (e *EventError) Unwrap() error {
+	if e.Status == nil {
+		return nil
+	}
+	return &apierrors.StatusError{ErrStatus: *e.Status}
+}
+
+// Decode returns the project carried by event. Bookmark events return a
+// nil project and no error. watch.Error events and events carrying
+// anything other than a Project return an *EventError.
+func Decode(event watch.Event) (*projectv1.Project, error) {
+	switch event.Type {
+	case watch.Bookmark:
+		return nil, nil
+	case watch.Error:
+		e := &EventError{Type: event.Type, Object: event.Object}
+		if status, ok := event.Object.(*metav1.Status); ok {
+			e.Status = status
+		}
+		return nil, e
+	}
+
+	p, ok := event.Object.(*projectv1.Project)
+	if !ok {
+		return nil, &EventError{Type: event.Type, Object: event.Object}
+	}
+	return p, nil
+}
+
+// Next decodes event for a loop over a Watcher's results. It returns the
+// project, or skip for events without one: bookmarks and events that
+// failed in a retryable way, which are logged. A non-nil error is
+// terminal; the Watcher closes its result channel after it.
+func Next(event watch.Event) (p *projectv1.Project, skip bool, err error) {
+	p, err = Decode(event)
+	if err != nil {
+		if !Retryable(err) {
+			return nil, false, err
+		}
+		log.Printf("Skipping event: %v", err)
+		return nil, true, nil
+	}
+	return p, p == nil, nil
+}
+
+// Retryable reports whether a watch can carry on after err: the resource
+// version expired, the server is overloaded or timed out, the connection
+// failed, or a single event could not be decoded. Errors such as
+// Forbidden, Unauthorized or Invalid are terminal.
+func Retryable(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return true
+	}
+
+	switch {
+	case isExpired(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsTooManyRequests(err),
+		apierrors.IsInternalError(err),
+		apierrors.IsServiceUnavailable(err):
+		return true
+	}
+	return false
+}
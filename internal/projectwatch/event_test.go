@@ -0,0 +1,42 @@
+package projectwatch
+
+import (
+	"testing"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestNext(t *testing.T) {
+	p := project("a", "1")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: projectv1.GroupName, Resource: "projects"}, "", nil)
+	expired := apierrors.NewResourceExpired("too old resource version")
+
+	tests := []struct {
+		name    string
+		event   watch.Event
+		want    *projectv1.Project
+		skip    bool
+		wantErr bool
+	}{
+		{"project", watch.Event{Type: watch.Modified, Object: p}, p, false, false},
+		{"bookmark", watch.Event{Type: watch.Bookmark, Object: project("", "2")}, nil, true, false},
+		{"unexpected object", watch.Event{Type: watch.Added, Object: &corev1.Namespace{}}, nil, true, false},
+		{"retryable error", watch.Event{Type: watch.Error, Object: &expired.ErrStatus}, nil, true, false},
+		{"terminal error", watch.Event{Type: watch.Error, Object: &forbidden.ErrStatus}, nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, skip, err := Next(tt.event)
+			if got != tt.want || skip != tt.skip || (err != nil) != tt.wantErr {
+				t.Errorf("Next() = %v, %v, %v; want %v, %v, error %v", got, skip, err, tt.want, tt.skip, tt.wantErr)
+			}
+			if tt.wantErr && !apierrors.IsForbidden(err) {
+				t.Errorf("Next() error = %v; want Forbidden", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Package projectwatch provides a project watch that survives server-side
+// timeouts, dropped connections and expired resource versions.
+package projectwatch
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectclientset "github.com/openshift/client-go/project/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// DefaultBackoff is used between reconnect attempts when Options.Backoff
+// is not set.
+var DefaultBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Second,
+}
+
+// Options controls a Watcher.
+type Options struct {
+	// ListOptions selects the projects to watch. Its ResourceVersion is
+	// where the watch starts; when empty, the current projects are listed
+	// first and delivered as Added events, like a plain watch does.
+	// TimeoutSeconds bounds each connection, not the Watcher.
+	ListOptions metav1.ListOptions
+	// Backoff is the delay between reconnect attempts.
+	Backoff *wait.Backoff
+	// ForwardBookmarks passes Bookmark events on to the caller. They are
+	// always used to advance the resource version.
+	ForwardBookmarks bool
+}
+
+// Watcher is a watch.Interface over projects that reconnects with backoff,
+// resumes from the last seen resourceVersion and relists when that version
+// has expired (410 Gone), turning the differences into Added, Modified and
+// Deleted events so no change is lost. Errors that cannot be retried (see
+// Retryable) are delivered as a final watch.Error event carrying a
+// *metav1.Status, after which the result channel is closed.
+type Watcher struct {
+	client projectclientset.Interface
+	opts   Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	result chan watch.Event
+	done   chan struct{}
+
+	resourceVersion string
+	known           map[string]*projectv1.Project
+}
+
+var _ watch.Interface = &Watcher{}
+
+// New starts watching projects. The watch runs until ctx is done or Stop
+// is called.
+func New(ctx context.Context, client projectclientset.Interface, opts Options) *Watcher {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		client:          client,
+		opts:            opts,
+		ctx:             ctx,
+		cancel:          cancel,
+		result:          make(chan watch.Event),
+		done:            make(chan struct{}),
+		resourceVersion: opts.ListOptions.ResourceVersion,
+		known:           map[string]*projectv1.Project{},
+	}
+	go w.run()
+	return w
+}
+
+func (w *Watcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// Stop ends the watch and waits until the result channel is closed.
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// ResourceVersion is the last resource version the Watcher has seen.
+// It is only safe to call after the result channel is closed.
+func (w *Watcher) ResourceVersion() string {
+	return w.resourceVersion
+}
+
+func (w *Watcher) backoff() wait.Backoff {
+	if w.opts.Backoff != nil {
+		return *w.opts.Backoff
+	}
+	return DefaultBackoff
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	defer close(w.result)
+
+	if w.resourceVersion == "" && !w.relist() {
+		return
+	}
+
+	backoff := w.backoff()
+	for {
+		opts := w.opts.ListOptions
+		opts.ResourceVersion = w.resourceVersion
+		opts.AllowWatchBookmarks = true
+
+		rw, err := w.client.ProjectV1().Projects().Watch(w.ctx, opts)
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			if isExpired(err) {
+				if !w.relist() {
+					return
+				}
+				continue
+			}
+			if !Retryable(err) {
+				w.fail(err)
+				return
+			}
+			log.Printf("Error starting project watch, retrying: %v", err)
+			if !w.sleep(backoff.Step()) {
+				return
+			}
+			continue
+		}
+
+		received, err := w.consume(rw)
+		rw.Stop()
+		if w.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if isExpired(err) {
+				if !w.relist() {
+					return
+				}
+				continue
+			}
+			if !Retryable(err) {
+				w.fail(err)
+				return
+			}
+			log.Printf("Project watch error, reconnecting: %v", err)
+		}
+		if received {
+			backoff = w.backoff()
+		}
+		if !w.sleep(backoff.Step()) {
+			return
+		}
+	}
+}
+
+// consume forwards the events of one connection until it closes. It
+// reports whether any event was received and the error, if the connection
+// ended with a watch.Error event.
+func (w *Watcher) consume(rw watch.Interface) (received bool, err error) {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return received, nil
+		case event, ok := <-rw.ResultChan():
+			if !ok {
+				return received, nil
+			}
+			received = true
+
+			switch event.Type {
+			case watch.Error:
+				_, err := Decode(event)
+				return received, err
+			case watch.Bookmark:
+				if m, err := meta.Accessor(event.Object); err == nil {
+					w.resourceVersion = m.GetResourceVersion()
+				}
+				if !w.opts.ForwardBookmarks {
+					continue
+				}
+			case watch.Added, watch.Modified:
+				if p, ok := event.Object.(*projectv1.Project); ok {
+					w.known[p.Name] = p
+					w.resourceVersion = p.ResourceVersion
+				}
+			case watch.Deleted:
+				if p, ok := event.Object.(*projectv1.Project); ok {
+					delete(w.known, p.Name)
+					w.resourceVersion = p.ResourceVersion
+				}
+			}
+
+			if !w.send(event) {
+				return received, nil
+			}
+		}
+	}
+}
+
+// relist lists the projects, emits the differences to what has been seen
+// so far and moves the resource version to that of the list. It retries
+// until it succeeds or the Watcher is stopped.
+func (w *Watcher) relist() bool {
+	backoff := w.backoff()
+	for {
+		opts := w.opts.ListOptions
+		opts.ResourceVersion = ""
+		opts.TimeoutSeconds = nil
+
+		list, err := w.client.ProjectV1().Projects().List(w.ctx, opts)
+		if err == nil {
+			return w.replace(list)
+		}
+		if w.ctx.Err() != nil {
+			return false
+		}
+		log.Printf("Error listing projects, retrying: %v", err)
+		if !w.sleep(backoff.Step()) {
+			return false
+		}
+	}
+}
+
+func (w *Watcher) replace(list *projectv1.ProjectList) bool {
+	current := make(map[string]*projectv1.Project, len(list.Items))
+	for i := range list.Items {
+		p := &list.Items[i]
+		current[p.Name] = p
+
+		old, ok := w.known[p.Name]
+		switch {
+		case !ok:
+			if !w.send(watch.Event{Type: watch.Added, Object: p}) {
+				return false
+			}
+		case old.ResourceVersion != p.ResourceVersion:
+			if !w.send(watch.Event{Type: watch.Modified, Object: p}) {
+				return false
+			}
+		}
+	}
+	for name, old := range w.known {
+		if _, ok := current[name]; !ok {
+			if !w.send(watch.Event{Type: watch.Deleted, Object: old}) {
+				return false
+			}
+		}
+	}
+
+	w.known = current
+	w.resourceVersion = list.ResourceVersion
+	return true
+}
+
+// fail delivers err as the final watch.Error event.
+func (w *Watcher) fail(err error) {
+	status := metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+		Reason:  metav1.StatusReasonUnknown,
+	}
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		status = apiStatus.Status()
+	}
+	w.send(watch.Event{Type: watch.Error, Object: &status})
+}
+
+func (w *Watcher) send(event watch.Event) bool {
+	select {
+	case w.result <- event:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+func (w *Watcher) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+func isExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
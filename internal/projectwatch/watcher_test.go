@@ -0,0 +1,196 @@
+package projectwatch
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	projectv1 "github.com/openshift/api/project/v1"
+	projectfake "github.com/openshift/client-go/project/clientset/versioned/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var testBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 1000}
+
+func project(name, rv string) *projectv1.Project {
+	return &projectv1.Project{ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: rv}}
+}
+
+func projectList(rv string, items ...*projectv1.Project) *projectv1.ProjectList {
+	list := &projectv1.ProjectList{ListMeta: metav1.ListMeta{ResourceVersion: rv}}
+	for _, p := range items {
+		list.Items = append(list.Items, *p)
+	}
+	return list
+}
+
+// fakeServer answers list and watch requests from prepared responses and
+// records the resource versions the watches started from.
+type fakeServer struct {
+	mu       sync.Mutex
+	lists    []*projectv1.ProjectList
+	watches  []*watch.FakeWatcher
+	watchRVs []string
+}
+
+func (s *fakeServer) client() *projectfake.Clientset {
+	client := projectfake.NewClientset()
+	client.PrependReactor("list", "projects", func(clienttesting.Action) (bool, runtime.Object, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		list := s.lists[0]
+		s.lists = s.lists[1:]
+		return true, list, nil
+	})
+	client.PrependWatchReactor("projects", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.watchRVs = append(s.watchRVs, action.(clienttesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion)
+		if len(s.watches) == 0 {
+			// Keep the last connection open until the Watcher is stopped.
+			return true, watch.NewFake(), nil
+		}
+		fw := s.watches[0]
+		s.watches = s.watches[1:]
+		return true, fw, nil
+	})
+	return client
+}
+
+// closedWatch returns a watch that delivers events and then closes, like
+// a connection ended by the server.
+func closedWatch(events ...watch.Event) *watch.FakeWatcher {
+	fw := watch.NewFakeWithChanSize(len(events), false)
+	for _, e := range events {
+		fw.Action(e.Type, e.Object)
+	}
+	fw.Stop()
+	return fw
+}
+
+func describe(e watch.Event) string {
+	if p, ok := e.Object.(*projectv1.Project); ok {
+		return string(e.Type) + " " + p.Name + " " + p.ResourceVersion
+	}
+	if s, ok := e.Object.(*metav1.Status); ok {
+		return string(e.Type) + " " + string(s.Reason)
+	}
+	return string(e.Type)
+}
+
+func receive(t *testing.T, w *Watcher, n int) []string {
+	t.Helper()
+	var got []string
+	for len(got) < n {
+		select {
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				t.Fatalf("result channel closed after %v", got)
+			}
+			got = append(got, describe(e))
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out after %v", got)
+		}
+	}
+	return got
+}
+
+func TestWatcherReconnectAndRelist(t *testing.T) {
+	expired := apierrors.NewResourceExpired("too old resource version")
+
+	tests := []struct {
+		name    string
+		forward bool
+	}{
+		{"bookmarks consumed", false},
+		{"bookmarks forwarded", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeServer{
+				lists: []*projectv1.ProjectList{
+					projectList("10", project("a", "1"), project("b", "2"), project("d", "3")),
+					// After the 410: a is unchanged, b was modified, c was
+					// added and d deleted while the watch was down.
+					projectList("30", project("a", "11"), project("b", "20"), project("c", "21")),
+				},
+				watches: []*watch.FakeWatcher{
+					closedWatch(
+						watch.Event{Type: watch.Modified, Object: project("a", "11")},
+						watch.Event{Type: watch.Bookmark, Object: project("", "12")},
+					),
+					closedWatch(watch.Event{Type: watch.Error, Object: &expired.ErrStatus}),
+				},
+			}
+
+			w := New(context.Background(), s.client(), Options{Backoff: &testBackoff, ForwardBookmarks: tt.forward})
+			defer w.Stop()
+
+			want := []string{"ADDED a 1", "ADDED b 2", "ADDED d 3", "MODIFIED a 11"}
+			if tt.forward {
+				want = append(want, "BOOKMARK  12")
+			}
+			want = append(want, "MODIFIED b 20", "ADDED c 21", "DELETED d 3")
+			if got := receive(t, w, len(want)); !reflect.DeepEqual(got, want) {
+				t.Errorf("events = %q; want %q", got, want)
+			}
+
+			// The first watch starts at the list, the second resumes from the
+			// bookmark, the third starts at the relist.
+			waitFor(t, func() bool {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				return len(s.watchRVs) == 3
+			})
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if want := []string{"10", "12", "30"}; !reflect.DeepEqual(s.watchRVs, want) {
+				t.Errorf("watch resource versions = %q; want %q", s.watchRVs, want)
+			}
+		})
+	}
+}
+
+func TestWatcherNonRetryableError(t *testing.T) {
+	client := projectfake.NewClientset()
+	client.PrependWatchReactor("projects", func(clienttesting.Action) (bool, watch.Interface, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: projectv1.GroupName, Resource: "projects"}, "", nil)
+	})
+
+	w := New(context.Background(), client, Options{
+		ListOptions: metav1.ListOptions{ResourceVersion: "10"},
+		Backoff:     &testBackoff,
+	})
+	defer w.Stop()
+
+	if got, want := receive(t, w, 1), []string{"ERROR Forbidden"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q; want %q", got, want)
+	}
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Error("result channel still open after the final error")
+		}
+	case <-time.After(10 * time.Second):
+		t.Error("result channel not closed after the final error")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}